@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PlayBuzzer plays round in buzzer mode: prompts are shown one at a time and
+// the first player to give a valid answer takes each one. A prompt is skipped
+// if nobody claims it within BUZZER_TIMEOUT or SKIP_KEY is entered. It
+// returns TIME_UP once every prompt is done, ENDED_EARLY if the round was
+// interrupted and QUIT if the players quit or input ran out.
+func (g *Game) PlayBuzzer(round *Round, in *interrupter) timerResult {
+	fmt.Fprintln(g.out, SEP)
+	printLetters(g.out, round.Letters)
+	example := msg("buzz-example")
+	if len(round.Letters) > 0 {
+		example = string(round.Letters[0]) + "..."
+	}
+	fmt.Fprintf(g.out, msg("buzz-intro")+"\n", PLAYERS[0], example, SKIP_KEY, PAUSE_KEY, QUIT_KEY)
+	fmt.Fprintln(g.out, "")
+
+	ctx := in.startRound()
+	start := g.Clock.Now()
+	result := TIME_UP
+	for i, prompt := range round.Prompts {
+		fmt.Fprintf(g.out, PROMPT_FORMAT+"\n", painted{COLOR_DIM, i + 1}, prompt)
+		timer := newRoundTimer(BUZZER_TIMEOUT, g.Clock.Now())
+		g.mu.Lock()
+		g.live = liveRound{round, timer}
+		g.mu.Unlock()
+		if result = buzz(ctx, g.out, g.Clock, timer, round, i, g.lines); result != TIME_UP {
+			break
+		}
+	}
+	in.endRound()
+	g.mu.Lock()
+	g.live.timer = nil
+	g.Stats.record(*round, result, g.Clock.Now().Sub(start))
+	g.mu.Unlock()
+	switch result {
+	case TIME_UP:
+		fmt.Fprintln(g.out, msg("every-prompt"))
+	case ENDED_EARLY:
+		fmt.Fprintln(g.out, msg("ended-early"))
+	case QUIT:
+		fmt.Fprintln(g.out, msg("bye"))
+	}
+	return result
+}
+
+// buzz reads buzzes for one of round's prompts from lines, recording each on
+// the round, until a valid one claims the prompt, it's skipped or timer runs
+// out on clock, and then returns TIME_UP. The timer is the prompt's live one,
+// so extending it gives the players longer. PAUSE_KEY pauses or resumes the
+// whole game, and nobody can buzz in while it's paused. It returns
+// ENDED_EARLY if ctx is cancelled first, and QUIT on QUIT_KEY or when lines
+// runs out.
+func buzz(ctx context.Context, w io.Writer, clock Clock, timer *roundTimer, round *Round, prompt int, lines <-chan string) timerResult {
+	for {
+		remaining := timer.Remaining(clock.Now())
+		if remaining <= 0 {
+			fmt.Fprintln(w, "    "+msg("buzz-nobody"))
+			return TIME_UP
+		}
+		select {
+		case <-ctx.Done():
+			return ENDED_EARLY
+		case <-clock.After(remaining):
+			// Checked again at the top, in case the timer was extended
+		case line, ok := <-lines:
+			if !ok {
+				return QUIT
+			}
+			switch strings.TrimSpace(line) {
+			case "":
+				continue
+			case SKIP_KEY:
+				fmt.Fprintln(w, "    "+msg("skipped"))
+				return TIME_UP
+			case QUIT_KEY:
+				return QUIT
+			case PAUSE_KEY:
+				if togglePause(clock) {
+					fmt.Fprintf(w, "    "+msg("paused")+"\n", PAUSE_KEY)
+				} else {
+					fmt.Fprintln(w, "    "+msg("resumed"))
+				}
+				continue
+			}
+			if clockPaused(clock) {
+				fmt.Fprintf(w, "    "+msg("paused")+"\n", PAUSE_KEY)
+				continue
+			}
+			player, text, ok := parseBuzz(line, PLAYERS)
+			if !ok {
+				fmt.Fprintln(w, "    "+msg("buzz-who"))
+				continue
+			}
+			answer := newAnswer(round, player, prompt, text)
+			round.Answers = append(round.Answers, answer)
+			if !answer.Valid {
+				fmt.Fprintf(w, "    "+msg("buzz-wrong")+"\n", player, answer.Reason)
+				continue
+			}
+			fmt.Fprintf(w, "    "+msg("buzz-right")+"\n", player, text)
+			return TIME_UP
+		}
+	}
+}
+
+// parseBuzz splits a buzz into the player, given by name or 1-based number,
+// and their answer.
+func parseBuzz(line string, players []string) (player, text string, ok bool) {
+	who, text, _ := strings.Cut(strings.TrimSpace(line), " ")
+	text = strings.TrimSpace(text)
+	if n, err := strconv.Atoi(who); err == nil && n >= 1 && n <= len(players) {
+		return players[n-1], text, true
+	}
+	for _, p := range players {
+		if strings.EqualFold(p, who) {
+			return p, text, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits, so timers can run on something other than
+// the wall clock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the wall clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// pausableClock runs on another Clock but can be stopped for the whole game:
+// while it's paused Now stands still and nothing from After fires, and once
+// it resumes both carry on from where they were, so nothing timed on it loses
+// any time to the pause. Its Now runs behind the other clock by the time
+// spent paused.
+type pausableClock struct {
+	Clock
+	mu       sync.Mutex
+	pausedAt time.Time     // when the current pause began on Clock; zero while running
+	behind   time.Duration // time spent in earlier pauses
+	changed  chan struct{} // closed and replaced whenever the clock pauses or resumes
+}
+
+func newPausableClock(clock Clock) *pausableClock {
+	return &pausableClock{Clock: clock, changed: make(chan struct{})}
+}
+
+func (c *pausableClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nowLocked()
+}
+
+func (c *pausableClock) nowLocked() time.Time {
+	if !c.pausedAt.IsZero() {
+		return c.pausedAt.Add(-c.behind)
+	}
+	return c.Clock.Now().Add(-c.behind)
+}
+
+// After fires once d has passed on the clock, not counting any time paused.
+func (c *pausableClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	deadline := c.Now().Add(d)
+	go func() {
+		for {
+			c.mu.Lock()
+			now, paused, changed := c.nowLocked(), !c.pausedAt.IsZero(), c.changed
+			c.mu.Unlock()
+			var wake <-chan time.Time // nil, so never fires, while paused
+			if !paused {
+				left := deadline.Sub(now)
+				if left <= 0 {
+					ch <- now
+					return
+				}
+				wake = c.Clock.After(left)
+			}
+			select {
+			case <-wake:
+			case <-changed:
+			}
+		}
+	}()
+	return ch
+}
+
+func (c *pausableClock) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.pausedAt.IsZero()
+}
+
+// Toggle pauses the clock or resumes it, and reports whether it's now paused.
+func (c *pausableClock) Toggle() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now := c.Clock.Now(); c.pausedAt.IsZero() {
+		c.pausedAt = now
+	} else {
+		c.behind += now.Sub(c.pausedAt)
+		c.pausedAt = time.Time{}
+	}
+	close(c.changed)
+	c.changed = make(chan struct{})
+	return !c.pausedAt.IsZero()
+}
+
+// togglePause pauses the whole game running on clock, or resumes it, and
+// reports whether it's now paused: always false for a clock that can't be.
+func togglePause(clock Clock) bool {
+	c, ok := clock.(*pausableClock)
+	return ok && c.Toggle()
+}
+
+// clockPaused reports whether the whole game running on clock is paused.
+func clockPaused(clock Clock) bool {
+	c, ok := clock.(*pausableClock)
+	return ok && c.Paused()
+}
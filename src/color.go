@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// ANSI codes for colorized output, applied with paint.
+const (
+	COLOR_RESET  = "\033[0m"
+	COLOR_LETTER = "\033[1;96m" // bold bright cyan
+	COLOR_DIM    = "\033[2m"
+	COLOR_GREEN  = "\033[32m"
+	COLOR_YELLOW = "\033[33m"
+	COLOR_RED    = "\033[31m"
+)
+
+// The timer turns yellow, then red, once this fraction of the round is left.
+const (
+	TIMER_YELLOW_AT = 0.5
+	TIMER_RED_AT    = 0.2
+)
+
+// paint wraps s in the given color code if COLOR is on.
+func paint(code, s string) string {
+	if !COLOR {
+		return s
+	}
+	return code + s + COLOR_RESET
+}
+
+// timerColor picks the timer's color for how much of total is left.
+func timerColor(remaining, total time.Duration) string {
+	left := 0.0
+	if total > 0 {
+		left = float64(remaining) / float64(total)
+	}
+	switch {
+	case left <= TIMER_RED_AT:
+		return COLOR_RED
+	case left <= TIMER_YELLOW_AT:
+		return COLOR_YELLOW
+	default:
+		return COLOR_GREEN
+	}
+}
+
+// isTerminal reports whether f is attached to a terminal rather than a file
+// or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
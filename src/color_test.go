@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoColorWhenNotATerminal(t *testing.T) {
+	out, code := runGame(t, "", "-color", "-rounds", "1", "-no-wait", "-seed", "7")
+	if code != 0 {
+		t.Fatalf("exited %d:\n%s", code, out)
+	}
+	if !strings.Contains(out, "Time's up!") {
+		t.Fatalf("didn't play a round:\n%s", out)
+	}
+	if strings.Contains(out, "\033[") {
+		t.Errorf("-color wrote color codes to a pipe:\n%q", out)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if isTerminal(file) {
+		t.Error("a plain file counts as a terminal")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	set(t, &COLOR, false)
+	if got := paint(COLOR_RED, "Time's up!"); got != "Time's up!" {
+		t.Errorf("paint with COLOR off = %q, want the text alone", got)
+	}
+	COLOR = true
+	if got := paint(COLOR_RED, "Time's up!"); got != COLOR_RED+"Time's up!"+COLOR_RESET {
+		t.Errorf("paint with COLOR on = %q, want it wrapped in red", got)
+	}
+}
+
+func TestTimerColor(t *testing.T) {
+	for _, test := range []struct {
+		remaining time.Duration
+		want      string
+	}{
+		{100 * time.Second, COLOR_GREEN},
+		{51 * time.Second, COLOR_GREEN},
+		{50 * time.Second, COLOR_YELLOW},
+		{21 * time.Second, COLOR_YELLOW},
+		{20 * time.Second, COLOR_RED},
+		{0, COLOR_RED},
+	} {
+		if got := timerColor(test.remaining, 100*time.Second); got != test.want {
+			t.Errorf("timerColor(%s of 100s) = %q, want %q", test.remaining, got, test.want)
+		}
+	}
+}
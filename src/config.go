@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Settings are resolved in five layers, each overriding the one before:
+//
+//  1. the built-in defaults declared in main.go,
+//  2. the -difficulty preset, from DIFFICULTIES,
+//  3. the -blitz preset, BLITZ_PRESET,
+//  4. the JSON file named by -config,
+//  5. flags given on the command line.
+//
+// A config file is an object keyed by flag name, with values written as JSON
+// strings, numbers, booleans, arrays for comma-separated flags, or objects
+// for comma-separated KEY=VALUE flags:
+//
+//	{
+//	  "duration": "2m",
+//	  "prompts": 10,
+//	  "letters": "ABCDEFGHIJKLMNOPRSTW",
+//	  "players": ["Alice", "Bob"],
+//	  "letter-weights": {"S": 3, "K": 0.5, "Z": 0},
+//	  "seed": 42,
+//	  "silent": true
+//	}
+//
+// Keys that don't name a flag are warned about and ignored.
+
+// applyConfig sets the flags in fs from the config file at path, leaving any
+// flag already set on the command line alone.
+func applyConfig(fs *flag.FlagSet, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	settings := map[string]any{}
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	if err := decoder.Decode(&settings); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	for key, value := range settings {
+		if fs.Lookup(key) == nil || key == "config" {
+			slog.Warn("ignoring unknown setting", "config", path, "key", key)
+			continue
+		}
+		if explicit[key] {
+			continue
+		}
+		if err := fs.Set(key, configValue(value)); err != nil {
+			return fmt.Errorf("%s: setting %q: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+// DIFFICULTIES are the -difficulty presets, as flag values keyed by flag
+// name. Easy rounds run longer with fewer prompts and leave out the awkward
+// letters; hard ones are shorter, with more prompts and every letter.
+var DIFFICULTIES = map[string]map[string]string{
+	"easy":   {"duration": "4m", "prompts": "8", "letters": "ABCDEFGHILMNOPRSTW"},
+	"normal": {},
+	"hard":   {"duration": "2m", "prompts": "15", "letters": "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+}
+
+// BLITZ_PRESET is the -blitz preset: ten 30-second rounds of a single prompt
+// each, played back to back with no countdown or summaries in between.
+// -rounds and -duration still set how many and how long.
+var BLITZ_PRESET = map[string]string{
+	"rounds":     "10",
+	"duration":   "30s",
+	"prompts":    "1",
+	"countdown":  "0",
+	"no-summary": "true",
+}
+
+// applyDifficulty sets the flags in fs from the named preset, leaving any
+// flag already set, on the command line or from a config file, alone.
+func applyDifficulty(fs *flag.FlagSet, name string) error {
+	preset, ok := DIFFICULTIES[name]
+	if !ok {
+		return fmt.Errorf("%q isn't easy, normal or hard", name)
+	}
+	return applyPreset(fs, preset)
+}
+
+// applyPreset sets the flags in fs from preset, leaving any flag already set
+// alone.
+func applyPreset(fs *flag.FlagSet, preset map[string]string) error {
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	for key, value := range preset {
+		if explicit[key] {
+			continue
+		}
+		if err := fs.Set(key, value); err != nil {
+			return fmt.Errorf("setting %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// configValue formats a decoded JSON value the way it'd be written as a flag.
+func configValue(value any) string {
+	switch v := value.(type) {
+	case []any:
+		items := []string{}
+		for _, item := range v {
+			items = append(items, configValue(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := []string{}
+		for _, key := range keys {
+			items = append(items, key+"="+configValue(v[key]))
+		}
+		return strings.Join(items, ",")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
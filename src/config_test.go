@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testSettings are a few settings of each kind the config file can set, on
+// their own flag set.
+type testSettings struct {
+	fs       *flag.FlagSet
+	duration time.Duration
+	prompts  int
+	letters  string
+	players  string
+	weights  string
+	seed     int64
+	silent   bool
+}
+
+func newTestSettings() *testSettings {
+	s := &testSettings{fs: flag.NewFlagSet("test", flag.ContinueOnError)}
+	s.fs.SetOutput(io.Discard)
+	s.fs.DurationVar(&s.duration, "duration", 3*time.Minute, "")
+	s.fs.IntVar(&s.prompts, "prompts", 12, "")
+	s.fs.StringVar(&s.letters, "letters", string(LETTERS), "")
+	s.fs.StringVar(&s.players, "players", "", "")
+	s.fs.StringVar(&s.weights, "letter-weights", "", "")
+	s.fs.Int64Var(&s.seed, "seed", 0, "")
+	s.fs.BoolVar(&s.silent, "silent", false, "")
+	s.fs.String("config", "", "")
+	return s
+}
+
+// writeTestFile writes contents to a file named name in a temporary
+// directory and returns its path.
+func writeTestFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const SAMPLE_CONFIG = `{
+  "duration": "2m",
+  "prompts": 10,
+  "letters": "ABCDEFGHIJKLMNOPRSTW",
+  "players": ["Alice", "Bob"],
+  "letter-weights": {"S": 3, "K": 0.5, "Z": 0},
+  "seed": 42,
+  "silent": true,
+  "colour": true
+}`
+
+func TestApplyConfig(t *testing.T) {
+	s := newTestSettings()
+	if err := s.fs.Parse([]string{"-prompts", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfig(s.fs, writeTestFile(t, "game.json", SAMPLE_CONFIG)); err != nil {
+		t.Fatalf("applyConfig: %v", err)
+	}
+	if s.duration != 2*time.Minute {
+		t.Errorf("duration %s, want the file's 2m", s.duration)
+	}
+	if s.prompts != 5 {
+		t.Errorf("%d prompts, want the command line's 5 over the file's 10", s.prompts)
+	}
+	if s.letters != "ABCDEFGHIJKLMNOPRSTW" {
+		t.Errorf("letters %q, want the file's", s.letters)
+	}
+	if s.players != "Alice,Bob" {
+		t.Errorf("players %q, want Alice,Bob", s.players)
+	}
+	if s.weights != "K=0.5,S=3,Z=0" {
+		t.Errorf("letter weights %q, want K=0.5,S=3,Z=0", s.weights)
+	}
+	if s.seed != 42 || !s.silent {
+		t.Errorf("seed %d, silent %v; want 42 and true", s.seed, s.silent)
+	}
+}
+
+func TestApplyConfigErrors(t *testing.T) {
+	for _, test := range []struct {
+		name, contents string
+	}{
+		{"not JSON", "duration: 2m"},
+		{"bad value", `{"prompts": "lots"}`},
+	} {
+		if err := applyConfig(newTestSettings().fs, writeTestFile(t, "game.json", test.contents)); err == nil {
+			t.Errorf("%s: applyConfig succeeded, want an error", test.name)
+		}
+	}
+	if err := applyConfig(newTestSettings().fs, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("applyConfig of a missing file succeeded, want an error")
+	}
+}
+
+func TestConfigValue(t *testing.T) {
+	for _, test := range []struct {
+		value any
+		want  string
+	}{
+		{"2m", "2m"},
+		{true, "true"},
+		{nil, ""},
+		{[]any{"Alice", "Bob"}, "Alice,Bob"},
+		{map[string]any{"S": 3, "K": 0.5}, "K=0.5,S=3"},
+	} {
+		if got := configValue(test.value); got != test.want {
+			t.Errorf("configValue(%#v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestApplyDifficulty(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		args     []string
+		duration time.Duration
+		prompts  int
+		letters  string
+	}{
+		{"easy", nil, 4 * time.Minute, 8, "ABCDEFGHILMNOPRSTW"},
+		{"hard", nil, 2 * time.Minute, 15, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{"normal", nil, 3 * time.Minute, 12, string(LETTERS)},
+		{"easy", []string{"-prompts", "10"}, 4 * time.Minute, 10, "ABCDEFGHILMNOPRSTW"},
+		{"hard", []string{"-duration", "90s", "-letters", "ABC"}, 90 * time.Second, 15, "ABC"},
+	} {
+		s := newTestSettings()
+		if err := s.fs.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyDifficulty(s.fs, test.name); err != nil {
+			t.Fatalf("%s %v: %v", test.name, test.args, err)
+		}
+		if s.duration != test.duration || s.prompts != test.prompts || s.letters != test.letters {
+			t.Errorf("%s %v: %s, %d prompts, letters %q; want %s, %d and %q", test.name, test.args, s.duration, s.prompts, s.letters, test.duration, test.prompts, test.letters)
+		}
+	}
+	if err := applyDifficulty(newTestSettings().fs, "nightmare"); err == nil {
+		t.Error("applied an unknown difficulty")
+	}
+}
+
+func TestConfigOverridesDifficulty(t *testing.T) {
+	s := newTestSettings()
+	if err := applyConfig(s.fs, writeTestFile(t, "game.json", `{"prompts": 6}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyDifficulty(s.fs, "hard"); err != nil {
+		t.Fatal(err)
+	}
+	if s.prompts != 6 || s.duration != 2*time.Minute {
+		t.Errorf("%d prompts over %s, want the file's 6 over hard's 2m", s.prompts, s.duration)
+	}
+}
+
+func TestBlitzPreset(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		rounds   int
+		duration time.Duration
+	}{
+		{nil, 10, 30 * time.Second},
+		{[]string{"-rounds", "5", "-duration", "20s"}, 5, 20 * time.Second},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		rounds := fs.Int("rounds", 0, "")
+		duration := fs.Duration("duration", 3*time.Minute, "")
+		prompts := fs.Int("prompts", 12, "")
+		countdown := fs.Int("countdown", 3, "")
+		noSummary := fs.Bool("no-summary", false, "")
+		if err := fs.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyPreset(fs, BLITZ_PRESET); err != nil {
+			t.Fatal(err)
+		}
+		if *rounds != test.rounds || *duration != test.duration || *prompts != 1 || *countdown != 0 || !*noSummary {
+			t.Errorf("%v: %d rounds of %s, %d prompts, countdown %d, summaries off %v; want %d of %s, 1, 0 and off",
+				test.args, *rounds, *duration, *prompts, *countdown, *noSummary, test.rounds, test.duration)
+		}
+	}
+}
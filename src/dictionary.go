@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Dictionary is a set of known words and phrases, lowercased.
+type Dictionary map[string]bool
+
+// loadDictionary reads a word list with one word or phrase per line. Blank
+// lines and lines starting with # are skipped.
+func loadDictionary(path string) (Dictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dict := Dictionary{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		dict[strings.ToLower(line)] = true
+	}
+	return dict, scanner.Err()
+}
+
+// Contains reports whether text is in the dictionary, ignoring case. Unless
+// wholePhrase is set only its first word is looked up, after any leading
+// article if skipArticles is set.
+func (d Dictionary) Contains(text string, wholePhrase, skipArticles bool) bool {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return false
+	}
+	if wholePhrase {
+		return d[strings.Join(words, " ")]
+	}
+	if skipArticles && len(words) > 1 && isArticle(words[0]) {
+		words = words[1:]
+	}
+	return d[words[0]]
+}
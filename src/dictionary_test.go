@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const SAMPLE_WORDS = `# animals
+Bear
+bison
+
+Blue Whale
+  badger  
+`
+
+func TestLoadDictionary(t *testing.T) {
+	dict, err := loadDictionary(writeTestFile(t, "words.txt", SAMPLE_WORDS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dict) != 4 {
+		t.Errorf("loaded %d entries, want 4: %v", len(dict), dict)
+	}
+	for _, test := range []struct {
+		text                      string
+		wholePhrase, skipArticles bool
+		want                      bool
+	}{
+		{"Bear", false, false, true},
+		{"BISON", false, false, true},
+		{" badger ", false, false, true},
+		{"Beaver", false, false, false},
+		{"", false, false, false},
+		{"Bear Cub", false, false, true},
+		{"Bear Cub", true, false, false},
+		{"blue   whale", true, false, true},
+		{"Blue", false, false, false},
+		{"The Bear", false, false, false},
+		{"The Bear", false, true, true},
+		{"# animals", true, false, false},
+	} {
+		if got := dict.Contains(test.text, test.wholePhrase, test.skipArticles); got != test.want {
+			t.Errorf("Contains(%q, %v, %v) = %v, want %v", test.text, test.wholePhrase, test.skipArticles, got, test.want)
+		}
+	}
+	if _, err := loadDictionary(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("loading a missing word list succeeded")
+	}
+}
+
+func TestNewAnswerChecksTheDictionary(t *testing.T) {
+	set(t, &DICTIONARY, Dictionary{"bear": true})
+	set(t, &STRICT_DICTIONARY, false)
+	round := Round{Letters: []rune("B"), Prompts: []string{"Animals"}}
+	if answer := newAnswer(&round, "Al", 0, "Bear"); !answer.Valid || answer.Unlisted {
+		t.Errorf("Bear: %+v, want valid and listed", answer)
+	}
+	if answer := newAnswer(&round, "Al", 0, "Bandersnatch"); !answer.Valid || !answer.Unlisted {
+		t.Errorf("Bandersnatch: %+v, want valid but unlisted", answer)
+	}
+	STRICT_DICTIONARY = true
+	if answer := newAnswer(&round, "Al", 0, "Bandersnatch"); answer.Valid || !answer.Unlisted {
+		t.Errorf("Bandersnatch with a strict dictionary: %+v, want invalid and unlisted", answer)
+	}
+}
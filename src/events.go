@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// event is one line of the -emit-json stream.
+type event struct {
+	Type      string         `json:"type"` // round_start, tick, round_end or score_update
+	Time      time.Time      `json:"time"`
+	Round     int            `json:"round"`
+	Letters   string         `json:"letters,omitempty"`           // round_start
+	Prompts   []string       `json:"prompts,omitempty"`           // round_start
+	Remaining *int           `json:"remaining_seconds,omitempty"` // tick
+	Paused    bool           `json:"paused,omitempty"`            // tick
+	Result    string         `json:"result,omitempty"`            // round_end
+	Points    map[string]int `json:"points,omitempty"`            // score_update: this round's
+	Totals    map[string]int `json:"totals,omitempty"`            // score_update: running
+}
+
+// eventStream writes events as JSON lines. It's safe for concurrent use.
+type eventStream struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newEventStream(w io.Writer) *eventStream {
+	return &eventStream{enc: json.NewEncoder(w)}
+}
+
+// Emit writes e, or does nothing if s is nil.
+func (s *eventStream) Emit(e event) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enc.Encode(e)
+}
+
+// emitView wraps a timerView to also emit a tick event each time the
+// seconds left on timer change, however often the timer redraws.
+type emitView struct {
+	timerView
+	events *eventStream
+	round  int
+	timer  *roundTimer
+	clock  Clock
+	last   int // seconds left at the last tick emitted, or -1
+	paused bool
+}
+
+func (v *emitView) Tick(w io.Writer, label, bar string, paused bool) {
+	v.timerView.Tick(w, label, bar, paused)
+	left := remainingSeconds(v.timer.Remaining(v.clock.Now()))
+	if left == v.last && paused == v.paused {
+		return
+	}
+	v.last, v.paused = left, paused
+	v.events.Emit(event{Type: "tick", Time: v.clock.Now(), Round: v.round, Remaining: &left, Paused: paused})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEvents decodes a -emit-json stream, failing on any line that isn't a
+// JSON event.
+func readEvents(t *testing.T, stream string) []event {
+	t.Helper()
+	events := []event{}
+	for i, line := range strings.Split(strings.TrimSuffix(stream, "\n"), "\n") {
+		var e event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %d: %v: %s", i+1, err, line)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestEventStream(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	set(t, &PLAYERS, []string{"Al", "Bo"})
+	// Start a round, end it, answer, then quit
+	script := "1\n" + SKIP_KEY + "\nApple\nAnt\nApple\n\n5\n"
+	g, out := scriptedGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("A"), Resolution: time.Second}, script)
+	g.Clock = newFakeClock()
+	var stream bytes.Buffer
+	g.Events = newEventStream(&stream)
+	g.Run(&interrupter{out: out})
+	if strings.Contains(out.String(), "{") {
+		t.Errorf("events in the human output:\n%s", out.String())
+	}
+
+	events := readEvents(t, stream.String())
+	types := []string{}
+	for _, e := range events {
+		types = append(types, e.Type)
+		if e.Round != 1 {
+			t.Errorf("%s event for round %d, want 1", e.Type, e.Round)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("%s event has no time", e.Type)
+		}
+	}
+	if got := strings.Join(types, " "); got != "round_start tick round_end score_update" {
+		t.Fatalf("emitted %s, want round_start tick round_end score_update", got)
+	}
+	start, tick, end, score := events[0], events[1], events[2], events[3]
+	if start.Letters != "A" || len(start.Prompts) != 2 {
+		t.Errorf("round_start has letters %q and prompts %v, want A and two prompts", start.Letters, start.Prompts)
+	}
+	if tick.Remaining == nil || *tick.Remaining != 60 || tick.Paused {
+		t.Errorf("tick has %v seconds left, paused %v; want 60, running", tick.Remaining, tick.Paused)
+	}
+	if end.Result != ENDED_EARLY.String() {
+		t.Errorf("round_end has result %q, want %q", end.Result, ENDED_EARLY.String())
+	}
+	if score.Points["Al"] != 1 || score.Points["Bo"] != 0 || score.Totals["Al"] != 1 {
+		t.Errorf("score_update has points %v and totals %v, want Al on 1 and Bo on 0", score.Points, score.Totals)
+	}
+}
+
+func TestTickEventsOncePerSecond(t *testing.T) {
+	set(t, &QUIET, true)
+	set(t, &BELLS, 0)
+	var out, stream bytes.Buffer
+	g := NewGame(Config{Duration: 3 * time.Second, NumPrompts: 1, Letters: []rune("A"), Resolution: 100 * time.Millisecond}, TEST_PROMPTS, 1, nil, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.Events = newEventStream(&stream)
+	round := g.NextRound()
+	if result := g.Play(&round, &interrupter{out: &out}); result != TIME_UP {
+		t.Fatalf("round ended with %v, want time up", result)
+	}
+	left := []int{}
+	var last time.Time
+	for _, e := range readEvents(t, stream.String()) {
+		if e.Type != "tick" {
+			continue
+		}
+		if e.Time.Before(last) {
+			t.Errorf("tick at %s came after one at %s", e.Time, last)
+		}
+		last = e.Time
+		left = append(left, *e.Remaining)
+	}
+	if got := fmt.Sprint(left); got != "[3 2 1]" {
+		t.Errorf("ticked with %s seconds left, want [3 2 1]", got)
+	}
+}
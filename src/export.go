@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var CSV_HEADER = []string{"round", "letter", "prompt", "player", "answer", "valid", "points"}
+
+// sessionInfo labels a themed game night in the output and exports. The zero
+// value labels nothing, leaving them as they'd be without it.
+type sessionInfo struct {
+	Title string
+	Date  string // e.g. 2026-10-14, or "" to leave it out
+}
+
+func (s sessionInfo) String() string {
+	if s.Date == "" {
+		return s.Title
+	}
+	return s.Title + ", " + s.Date
+}
+
+// csvExporter writes each round's answers to a CSV file, one row per answer.
+type csvExporter struct {
+	file    *os.File
+	w       *csv.Writer
+	session sessionInfo
+}
+
+// newCSVExporter creates or truncates the file at path and writes the header.
+// If session has a title, every row ends with title and date columns.
+func newCSVExporter(path string, session sessionInfo) (*csvExporter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	e := &csvExporter{file: file, w: csv.NewWriter(file), session: session}
+	header := CSV_HEADER
+	if session.Title != "" {
+		header = append(header[:len(header):len(header)], "title", "date")
+	}
+	if err := e.w.Write(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// WriteRound appends round's answers and flushes them to disk. Prompts
+// nobody answered still get a row, with the player columns left blank.
+func (e *csvExporter) WriteRound(round Round) error {
+	answered := map[int]bool{}
+	for _, answer := range round.Answers {
+		answered[answer.Prompt] = true
+		e.write([]string{
+			strconv.Itoa(round.Number),
+			string(round.Letters),
+			round.Prompts[answer.Prompt],
+			answer.Player,
+			answer.Text,
+			strconv.FormatBool(answer.Valid),
+			strconv.Itoa(answer.Points),
+		})
+	}
+	for i, prompt := range round.Prompts {
+		if !answered[i] {
+			e.write([]string{strconv.Itoa(round.Number), string(round.Letters), prompt, "", "", "", ""})
+		}
+	}
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return e.file.Sync()
+}
+
+// write writes a row, adding the session's title and date to the end of it
+// if it has a title.
+func (e *csvExporter) write(row []string) error {
+	if e.session.Title != "" {
+		row = append(row, e.session.Title, e.session.Date)
+	}
+	return e.w.Write(row)
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		e.file.Close()
+		return err
+	}
+	return e.file.Close()
+}
+
+// writeAnswersFile writes round's answers to a text file in dir named for
+// the round and its letters, e.g. round03_B.txt, with each player's answer
+// listed under every prompt. If that name's taken it adds a suffix, as in
+// round03_B-2.txt, rather than overwrite. A session with a title heads the
+// file. It returns the file's path.
+func writeAnswersFile(dir string, round Round, players []string, session sessionInfo) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	base := fmt.Sprintf("round%02d", round.Number)
+	if len(round.Letters) > 0 {
+		base += "_" + string(round.Letters)
+	}
+	var file *os.File
+	for n := 1; file == nil; n++ {
+		name := base + ".txt"
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d.txt", base, n)
+		}
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		file = f
+	}
+	w := bufio.NewWriter(file)
+	if session.Title != "" {
+		fmt.Fprintln(w, session)
+	}
+	fmt.Fprintf(w, msg("round")+"%s\n", round.Number, forLetters(round.Letters))
+	for i, prompt := range round.Prompts {
+		fmt.Fprintf(w, "\n%d. %s\n", i+1, prompt)
+		for _, player := range players {
+			texts := []string{}
+			for _, answer := range round.Answers {
+				if answer.Player == player && answer.Prompt == i && answer.Text != "" {
+					texts = append(texts, fmt.Sprintf("%s (%d)", answer.Text, answer.Points))
+				}
+			}
+			if len(texts) == 0 {
+				texts = append(texts, "-")
+			}
+			fmt.Fprintf(w, "   %s: %s\n", player, strings.Join(texts, ", "))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return "", err
+	}
+	return file.Name(), file.Close()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readCSV parses the CSV file at path.
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestCSVExportParsesBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.csv")
+	e, err := newCSVExporter(path, sessionInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := roundWith("B", map[string][]string{
+		"Al": {`Bob "the builder"`, "Bread, butter"},
+		"Bo": {"Bear\nhug", ""},
+	})
+	second := roundWith("C", map[string][]string{"Al": {"Cat"}})
+	second.Number = 2
+	second.Prompts = append(second.Prompts, "Unanswered")
+	for _, round := range []Round{first, second} {
+		scoreRound(&round, ScoringRules{Unique: 1})
+		if err := e.WriteRound(round); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := readCSV(t, path)
+	if !slices.Equal(rows[0], CSV_HEADER) {
+		t.Errorf("header = %q, want %q", rows[0], CSV_HEADER)
+	}
+	if want := 1 + len(first.Answers) + len(second.Answers) + 1; len(rows) != want {
+		t.Fatalf("got %d rows, want %d", len(rows), want)
+	}
+	for _, want := range [][]string{
+		{"1", "B", "Prompt", "Al", `Bob "the builder"`, "true", "1"},
+		{"1", "B", "Prompt", "Al", "Bread, butter", "true", "1"},
+		{"1", "B", "Prompt", "Bo", "Bear\nhug", "true", "1"},
+		{"2", "C", "Unanswered", "", "", "", ""},
+	} {
+		if !slices.ContainsFunc(rows, func(row []string) bool { return slices.Equal(row, want) }) {
+			t.Errorf("no row %q in %q", want, rows)
+		}
+	}
+}
+
+func TestWriteAnswersFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "answers")
+	round := roundWith("B", map[string][]string{"Al": {"Bear", "Banana"}, "Bo": {"Bear", ""}})
+	round.Number = 3
+	round.Prompts = []string{"Animals", "Fruits"}
+	scoreRound(&round, ScoringRules{Unique: 2, Duplicate: 1})
+	players := []string{"Al", "Bo", "Cy"}
+	want := `Round 3 for B
+
+1. Animals
+   Al: Bear (1)
+   Bo: Bear (1)
+   Cy: -
+
+2. Fruits
+   Al: Banana (2)
+   Bo: -
+   Cy: -
+`
+	// The same round written again, as after an undo, doesn't overwrite it
+	for _, name := range []string{"round03_B.txt", "round03_B-2.txt", "round03_B-3.txt"} {
+		path, err := writeAnswersFile(dir, round, players, sessionInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != filepath.Join(dir, name) {
+			t.Errorf("wrote %s, want %s", path, filepath.Join(dir, name))
+		}
+		if got, _ := os.ReadFile(path); string(got) != want {
+			t.Errorf("%s is\n%s\nwant\n%s", name, got, want)
+		}
+	}
+
+	round.Letters = []rune{}
+	round.Number = 12
+	path, err := writeAnswersFile(dir, round, players[:1], sessionInfo{"Game Night", "1 Mar 2024"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "round12.txt" {
+		t.Errorf("wrote %s for a round without letters, want round12.txt", path)
+	}
+	if got, _ := os.ReadFile(path); !strings.HasPrefix(string(got), "Game Night, 1 Mar 2024\nRound 12\n") {
+		t.Errorf("%s doesn't start with the title and round:\n%s", path, got)
+	}
+}
+
+func TestTitleInExports(t *testing.T) {
+	dir := t.TempDir()
+	session := sessionInfo{"Movie Night", "2024-03-01"}
+	round := roundWith("B", map[string][]string{"Al": {"Bambi"}})
+	round.Prompts = append(round.Prompts, "Unanswered")
+	scoreRound(&round, SCORING)
+
+	csvPath := filepath.Join(dir, "answers.csv")
+	e, err := newCSVExporter(csvPath, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.WriteRound(round); err != nil {
+		t.Fatal(err)
+	}
+	e.Close()
+	rows := readCSV(t, csvPath)
+	if want := append(slices.Clone(CSV_HEADER), "title", "date"); !slices.Equal(rows[0], want) {
+		t.Errorf("header = %q, want %q", rows[0], want)
+	}
+	if len(CSV_HEADER) != 7 {
+		t.Errorf("CSV_HEADER grew to %q", CSV_HEADER)
+	}
+	for _, row := range rows[1:] {
+		if len(row) != 9 || row[7] != "Movie Night" || row[8] != "2024-03-01" {
+			t.Errorf("row %q doesn't end with the title and date", row)
+		}
+	}
+
+	historyPath := filepath.Join(dir, "history.jsonl")
+	for _, session := range []sessionInfo{session, {}} {
+		history, err := openHistoryLog(historyPath, session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := history.WriteRound(round, nil, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		history.Close()
+	}
+	entries, err := loadHistory(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Title != "Movie Night" || entries[0].Date != "2024-03-01" {
+		t.Errorf("history has title %q and date %q, want the session's", entries[0].Title, entries[0].Date)
+	}
+	lines, _ := os.ReadFile(historyPath)
+	if untitled := strings.Split(string(lines), "\n")[1]; strings.Contains(untitled, `"title"`) || strings.Contains(untitled, `"date"`) {
+		t.Errorf("untitled history entry has title fields: %s", untitled)
+	}
+
+	answersPath, err := writeAnswersFile(dir, round, []string{"Al"}, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := os.ReadFile(answersPath); !strings.HasPrefix(string(text), "Movie Night, 2024-03-01\n") {
+		t.Errorf("answers file doesn't start with the title:\n%s", text)
+	}
+}
+
+func TestNoTitleLeavesExportsAlone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.csv")
+	e, err := newCSVExporter(path, sessionInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	round := roundWith("B", map[string][]string{"Al": {"Bambi"}})
+	if err := e.WriteRound(round); err != nil {
+		t.Fatal(err)
+	}
+	e.Close()
+	for _, row := range readCSV(t, path) {
+		if len(row) != len(CSV_HEADER) {
+			t.Errorf("row %q has %d columns, want %d", row, len(row), len(CSV_HEADER))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	FETCH_TIMEOUT = 10 * time.Second
+	FETCH_LIMIT   = 1 << 20 // largest prompt list we'll download, in bytes
+)
+
+// urlSource downloads prompts from a URL into a cache file, loading them
+// from fallback if there's neither a download nor a cached copy.
+type urlSource struct {
+	url      string
+	cache    string
+	fallback PromptSource
+}
+
+func (s urlSource) Prompts() ([]Prompt, error) {
+	path, err := fetchPrompts(s.url, s.cache)
+	if err != nil {
+		slog.Warn("can't download prompts", "url", s.url, "err", err)
+	}
+	if path == "" {
+		return s.fallback.Prompts()
+	}
+	return fileSource(path).Prompts()
+}
+
+// fetchPrompts downloads the prompt list at url into cache, so the usual
+// file loading can read it, and returns cache. If the download fails but an
+// earlier one is cached, it falls back to that copy.
+func fetchPrompts(url, cache string) (string, error) {
+	err := download(url, cache)
+	if err == nil {
+		return cache, nil
+	}
+	if _, statErr := os.Stat(cache); statErr == nil {
+		return cache, fmt.Errorf("%w; using the copy cached at %s", err, cache)
+	}
+	return "", err
+}
+
+// download saves the body of url to path, giving up after FETCH_TIMEOUT or
+// FETCH_LIMIT bytes.
+func download(url, path string) error {
+	client := &http.Client{Timeout: FETCH_TIMEOUT}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, FETCH_LIMIT+1))
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", url, err)
+	}
+	if len(body) > FETCH_LIMIT {
+		return fmt.Errorf("fetching %s: over the %d byte limit", url, FETCH_LIMIT)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, body)
+}
+
+// defaultCachePath picks where to cache the prompts downloaded from url, in
+// the user's cache directory.
+func defaultCachePath(url string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.New("no cache directory; set -prompts-cache")
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "scattergories", hex.EncodeToString(sum[:8])+".txt"), nil
+}
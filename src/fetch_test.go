@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const SAMPLE_PACK = "# online pack\nAnimals\n\nBands\nAnimals\nCars\n"
+
+// packServer serves SAMPLE_PACK until broken is set, then fails every
+// request.
+func packServer(t *testing.T, broken *atomic.Bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if broken.Load() {
+			http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(SAMPLE_PACK))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestURLSource(t *testing.T) {
+	set(t, &DEDUP_IGNORE_CASE, false)
+	var broken atomic.Bool
+	server := packServer(t, &broken)
+	cache := filepath.Join(t.TempDir(), "cache", "pack.txt")
+	fallback := promptList{{Text: "Fallback", Weight: 1}}
+	source := urlSource{server.URL, cache, fallback}
+
+	prompts, err := getPrompts(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := promptTexts(prompts); !slices.Equal(got, []string{"Animals", "Bands", "Cars"}) {
+		t.Errorf("downloaded %q, want the pack without its comment, blank line or duplicate", got)
+	}
+	if data, err := os.ReadFile(cache); err != nil || string(data) != SAMPLE_PACK {
+		t.Errorf("cached %q, %v; want the pack", data, err)
+	}
+
+	// Offline, the cached copy stands in
+	broken.Store(true)
+	prompts, err = getPrompts(source)
+	if got := promptTexts(prompts); err != nil || !slices.Equal(got, []string{"Animals", "Bands", "Cars"}) {
+		t.Errorf("with the server down loaded %q, %v; want the cached pack", got, err)
+	}
+
+	// With nothing cached either, the fallback does
+	source.cache = filepath.Join(t.TempDir(), "pack.txt")
+	prompts, err = getPrompts(source)
+	if got := promptTexts(prompts); err != nil || !slices.Equal(got, []string{"Fallback"}) {
+		t.Errorf("with nothing cached loaded %q, %v; want the fallback", got, err)
+	}
+}
+
+func TestDownloadLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("Animals\n", FETCH_LIMIT/8+1)))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "pack.txt")
+	if err := download(server.URL, path); err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Errorf("downloading an oversized pack = %v, want it refused", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("an oversized pack was saved: %v", err)
+	}
+}
+
+func TestFetchPromptsErrors(t *testing.T) {
+	var broken atomic.Bool
+	broken.Store(true)
+	server := packServer(t, &broken)
+	cache := filepath.Join(t.TempDir(), "pack.txt")
+	if path, err := fetchPrompts(server.URL, cache); path != "" || err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("fetchPrompts = %q, %v; want no path and the 503", path, err)
+	}
+	if err := os.WriteFile(cache, []byte("Cars\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := fetchPrompts(server.URL, cache); path != cache || err == nil || !strings.Contains(err.Error(), "cached") {
+		t.Errorf("fetchPrompts with a cached copy = %q, %v; want the copy and a warning", path, err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkFormat makes sure format's verbs line up with the argument kinds it
+// will be given, written as one letter per argument: d for an integer and s
+// for a string. This catches templates that would print %!d(string=...) or
+// %!(EXTRA ...) noise mid-game.
+func checkFormat(format, kinds string) error {
+	verbs := []rune{}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// Skip flags, width and precision to reach the verb
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+		if j == len(format) {
+			return fmt.Errorf("%q ends in the middle of a verb", format)
+		}
+		if format[j] != '%' {
+			verbs = append(verbs, rune(format[j]))
+		}
+		i = j
+	}
+	if len(verbs) != len(kinds) {
+		return fmt.Errorf("%q has %d verbs but takes %d values", format, len(verbs), len(kinds))
+	}
+	for i, verb := range verbs {
+		ok := verb == 'v'
+		switch kinds[i] {
+		case 'd':
+			ok = ok || strings.ContainsRune("dxXob", verb)
+		case 's':
+			ok = ok || strings.ContainsRune("sq", verb)
+		}
+		if !ok {
+			return fmt.Errorf("%q: verb %%%c can't format value %d", format, verb, i+1)
+		}
+	}
+	return nil
+}
+
+// painted formats value as usual, then paints it with code.
+type painted struct {
+	code  string
+	value any
+}
+
+func (p painted) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, paint(p.code, fmt.Sprintf(fmt.FormatString(f, verb), p.value)))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// playTestRound plays the first round of a game with config over
+// TEST_PROMPTS, on a clock that runs it straight to time up, and returns the
+// round and everything the game wrote.
+func playTestRound(t *testing.T, config Config) (Round, string) {
+	t.Helper()
+	set(t, &BELLS, 0)
+	var out bytes.Buffer
+	g := NewGame(config, TEST_PROMPTS, 1, nil, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	round := g.NextRound()
+	g.Play(&round, &interrupter{out: &out})
+	return round, out.String()
+}
+
+func TestRoundTemplates(t *testing.T) {
+	set(t, &SEP, "*** Game Night ***")
+	set(t, &LETTER_FORMAT, ">> %s <<")
+	set(t, &PROMPT_FORMAT, "[%02d] %s")
+	set(t, &TIMER_FORMAT, "%s to go")
+	set(t, &QUIET, false)
+	round, out := playTestRound(t, Config{Duration: 3 * time.Second, NumPrompts: 2, Letters: []rune("B"), Resolution: time.Second})
+	for _, want := range []string{
+		"*** Game Night ***\n",
+		">> B <<\n",
+		"[01] " + round.Prompts[0] + "\n",
+		"[02] " + round.Prompts[1] + "\n",
+		"0m3s to go",
+		"0m1s to go",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Letter:") || strings.Contains(out, "Remaining time") || strings.Contains(out, "%!") {
+		t.Errorf("output has default or broken formatting:\n%s", out)
+	}
+}
+
+func TestDefaultRoundTemplates(t *testing.T) {
+	set(t, &QUIET, true)
+	round, out := playTestRound(t, Config{Duration: time.Second, NumPrompts: 1, Letters: []rune("C"), Resolution: time.Second})
+	want := "===\nLetter: C\nPrompts:\n  1.\t" + round.Prompts[0] + "\n"
+	if !strings.HasPrefix(out, want) {
+		t.Errorf("round starts\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	for _, test := range []struct {
+		format, kinds string
+		ok            bool
+	}{
+		{"  %d.\t%s", "ds", true},
+		{"[%02d] %-20s", "ds", true},
+		{"%v: %q", "ds", true},
+		{"100%% sure: %s", "s", true},
+		{"Letter", "s", false},
+		{"%s %s", "s", false},
+		{"%d", "s", false},
+		{"%s.\t%s", "ds", false},
+		{"at %", "", false},
+		{"%5", "d", false},
+	} {
+		if err := checkFormat(test.format, test.kinds); (err == nil) != test.ok {
+			t.Errorf("checkFormat(%q, %q) = %v, want ok %v", test.format, test.kinds, err, test.ok)
+		}
+	}
+}
@@ -0,0 +1,579 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Config holds the settings a Game draws and times its rounds with.
+type Config struct {
+	Duration      time.Duration // length of each round
+	NumPrompts    int           // prompts drawn per round
+	MaxPrompts    int           // if above NumPrompts, each round draws a random number of prompts from NumPrompts up to this
+	Resolution    time.Duration // how often the timer redraws
+	Stagger       time.Duration // time over which prompts are revealed one by one, or 0 to show them all up front
+	NoLetter      bool          // draw only prompts, with no letter for answers to start with
+	LetterLast    bool          // show the prompts first and hold the letter back until a key is pressed
+	Letters       []rune        // letters rounds can use
+	RepeatLetters bool          // draw letters independently instead of cycling
+	LettersPer    int           // letters drawn per round; answers can start with any
+
+	// LetterWeights, if set, replaces the shuffled letter pool with weighted
+	// draws. Unlisted letters weigh 1; letters weighing 0 never come up.
+	LetterWeights map[rune]float64
+
+	// PromptWeights, if set, replaces the shuffled prompt pool with weighted
+	// draws the same way.
+	PromptWeights map[string]float64
+
+	Rounds int           // rounds to play back to back without waiting, or 0 to play until quit
+	Rest   time.Duration // pause between rounds that start on their own instead of from the menu, or 0
+
+	MaxRounds    int           // rounds after which the game ends, menu or not, or 0 for no limit
+	StartTimeout time.Duration // time the menu waits for a choice before starting a round anyway, or 0
+	SuddenDeath  bool          // play the last round of Rounds or MaxRounds at half the time for double points
+}
+
+// Game draws rounds from shuffled pools of letters and prompts, reshuffling
+// each pool once it can't fill a round. It reads player input from lines and
+// writes everything players see to out.
+type Game struct {
+	Config
+	Seed     int64
+	Played   int          // rounds drawn so far
+	Board    *Scoreboard  // running standings
+	Exporter *csvExporter // nil unless exporting answers
+	History  *historyLog  // nil unless logging rounds
+	Stats    Stats
+	Clock    Clock         // round timers run on this; the wall clock, pausable with PAUSE_KEY, unless replaced
+	Notifier Notifier      // nil unless notifying when rounds end
+	Events   *eventStream  // nil unless emitting JSON events
+	Memory   *letterMemory // nil unless remembering letters across runs
+	Scorer   Scorer        // how rounds with PLAYERS are scored
+	Preview  io.Writer     // nil unless showing the host the next round's letters
+
+	mu     sync.Mutex // guards Board, Stats and live, which are read off the main goroutine
+	live   liveRound
+	saving sync.Mutex // held while a round is written to the export, history and session files
+
+	prompts []string
+	rng     *rand.Rand
+	source  *countingSource // rng's source, for saving sessions
+	letters []rune          // the previous round's letters
+	last    *Round          // the previous round's draw, for replays
+	undo    *Session        // the game before the latest draw, for taking it back
+	recall  *Round          // the last round played through, answers and all, for recalling
+
+	// Practice, if set, replaces drawing with replaying these rounds in turn.
+	Practice   []historyEntry
+	practiced  int           // practice rounds started, including any looped over
+	practicing *historyEntry // the practice round being played
+	undoneTo   int           // practiced as of the latest draw, for taking it back
+	lines      <-chan string
+	starts     chan struct{} // starts a round as if enter was pressed at the menu
+	out        io.Writer
+
+	letterPool []rune
+	promptPool []string
+}
+
+func NewGame(config Config, prompts []string, seed int64, lines <-chan string, out io.Writer) *Game {
+	g := &Game{
+		Config:  config,
+		Seed:    seed,
+		Board:   NewScoreboard(),
+		Clock:   newPausableClock(realClock{}),
+		Scorer:  standardScorer{SCORING},
+		prompts: prompts,
+		source:  newCountingSource(seed),
+		lines:   lines,
+		starts:  make(chan struct{}),
+		out:     out,
+	}
+	g.rng = rand.New(g.source)
+	g.Reshuffle()
+	return g
+}
+
+// Reshuffle refills both pools with freshly shuffled letters and prompts.
+func (g *Game) Reshuffle() {
+	g.letterPool = shuffled(g.rng, g.Letters)
+	g.promptPool = shuffled(g.rng, g.prompts)
+}
+
+// Avoid moves letters to the back of the letter pool, keeping the shuffled
+// order otherwise, so they only come up once the rest have been drawn.
+func (g *Game) Avoid(letters []rune) {
+	fresh, avoided := []rune{}, []rune{}
+	for _, letter := range g.letterPool {
+		if slices.Contains(letters, letter) {
+			avoided = append(avoided, letter)
+		} else {
+			fresh = append(fresh, letter)
+		}
+	}
+	g.letterPool = append(fresh, avoided...)
+}
+
+// NextRound draws the next round's letters and prompts.
+func (g *Game) NextRound() Round {
+	g.snapshot()
+	if len(g.Practice) > 0 {
+		return g.nextPractice()
+	}
+	n := max(g.LettersPer, 1)
+	var letters []rune
+	shuffledLetters := false
+	switch {
+	case g.NoLetter:
+		letters = []rune{}
+	case len(g.LetterWeights) > 0:
+		letters = weightedLetters(g.rng, g.Letters, g.LetterWeights, g.letters, n)
+	case g.RepeatLetters:
+		for len(letters) < n {
+			if letter := g.Letters[g.rng.Intn(len(g.Letters))]; !slices.Contains(letters, letter) {
+				letters = append(letters, letter)
+			}
+		}
+	default:
+		if len(g.letterPool) < n {
+			slog.Debug("reshuffling letters")
+			shuffledLetters = true
+		}
+		letters, g.letterPool = draw(g.rng, g.Letters, g.letterPool, n, g.letters)
+	}
+	g.letters = letters
+	g.Played++
+	round := Round{Number: g.Played, Letters: letters, LettersShuffled: shuffledLetters}
+	count := g.promptCount()
+	if len(g.promptPool) < count && len(g.PromptWeights) == 0 {
+		slog.Debug("reshuffling prompts", "left", len(g.promptPool))
+		round.PromptsShuffled = true
+	}
+	lastPrompts := []string{}
+	if g.last != nil {
+		lastPrompts = g.last.Prompts
+	}
+	if len(g.PromptWeights) > 0 {
+		round.Prompts = weightedPrompts(g.rng, g.prompts, g.PromptWeights, lastPrompts, count)
+	} else {
+		round.Prompts, g.promptPool = draw(g.rng, g.prompts, g.promptPool, count, lastPrompts)
+	}
+	g.last = &Round{Letters: round.Letters, Prompts: round.Prompts}
+	return round
+}
+
+// promptCount picks how many prompts the next round draws: NumPrompts, or a
+// random number up to MaxPrompts, but never more prompts than there are.
+func (g *Game) promptCount() int {
+	n := g.NumPrompts
+	if g.MaxPrompts > n {
+		n += g.rng.Intn(g.MaxPrompts - n + 1)
+	}
+	return min(n, len(g.prompts))
+}
+
+// promptCountText describes the prompts drawn per round, e.g. "12" or "8-14".
+func (c Config) promptCountText() string {
+	if c.MaxPrompts > c.NumPrompts {
+		return fmt.Sprintf("%d-%d", c.NumPrompts, c.MaxPrompts)
+	}
+	return fmt.Sprint(c.NumPrompts)
+}
+
+// lookAhead runs draw and then puts the game back as it was, so draw can
+// call NextRound to see what's coming without using any of it up.
+func (g *Game) lookAhead(draw func()) {
+	saved, practiced, practicing, undo, undoneTo := g.Session(), g.practiced, g.practicing, g.undo, g.undoneTo
+	defer func() {
+		g.restore(saved)
+		g.practiced, g.practicing, g.undo, g.undoneTo = practiced, practicing, undo, undoneTo
+	}()
+	draw()
+}
+
+// Plan writes the letters and prompts of the next n rounds to w, drawing them
+// just as playing would and then putting the game back as it was, so the
+// rounds that follow are the ones shown.
+func (g *Game) Plan(w io.Writer, n int) {
+	g.lookAhead(func() {
+		for i := 0; i < n; i++ {
+			round := g.NextRound()
+			if len(round.Letters) > 0 {
+				fmt.Fprintf(w, msg("plan-round")+"\n", round.Number, letterText(round.Letters))
+			} else {
+				fmt.Fprintf(w, msg("round")+"\n", round.Number)
+			}
+			for j, prompt := range round.Prompts {
+				fmt.Fprintf(w, "  %d.\t%s\n", j+1, prompt)
+			}
+		}
+	})
+}
+
+// previewNext shows the host, on Preview, the letters the next round will
+// draw, unless there won't be one. It's worked out the way Plan does, so it
+// holds as long as the round being played isn't redrawn or taken back, which
+// show a fresh preview, and the next round isn't a replay or changed from the
+// menu.
+func (g *Game) previewNext() {
+	if g.Preview == nil || !g.more() {
+		return
+	}
+	g.lookAhead(func() {
+		next := g.NextRound()
+		fmt.Fprintf(g.Preview, msg("preview")+"\n", next.Number, letterText(next.Letters))
+	})
+}
+
+// ReplayRound starts a new round with the same letter and prompts as the
+// last one drawn, without touching the pools. It reports false if nothing has
+// been drawn yet.
+func (g *Game) ReplayRound() (Round, bool) {
+	if g.last == nil {
+		return Round{}, false
+	}
+	g.snapshot()
+	g.Played++
+	return Round{Number: g.Played, Letters: g.last.Letters, Prompts: g.last.Prompts, Replay: true}, true
+}
+
+// Redraw abandons a drawn round without it counting as played. Unless
+// DISCARD_REDRAWN is set, its letter and prompts are shuffled back into the
+// pools so they can come up again. A replay or practice round never took
+// them out of the pools, so it leaves them be.
+func (g *Game) Redraw(round Round) {
+	g.Played--
+	if DISCARD_REDRAWN || round.Replay {
+		return
+	}
+	g.letterPool = shuffled(g.rng, append(g.letterPool, round.Letters...))
+	g.promptPool = shuffled(g.rng, append(g.promptPool, round.Prompts...))
+}
+
+// OverrideLetter plays round with letter instead of the letters it drew. The
+// drawn letters are shuffled back into the pool, and letter is taken out of
+// it if it's there, so it isn't drawn again before the pool runs out.
+func (g *Game) OverrideLetter(round *Round, letter rune) {
+	if len(g.LetterWeights) == 0 && !g.RepeatLetters {
+		pool := slices.DeleteFunc(append(g.letterPool, round.Letters...), func(r rune) bool { return r == letter })
+		g.letterPool = shuffled(g.rng, pool)
+	}
+	round.Letters = []rune{letter}
+	g.letters = round.Letters
+	if g.last != nil {
+		g.last.Letters = round.Letters
+	}
+}
+
+// pickLetter offers to swap round's drawn letter for one the players choose,
+// asking again until the answer is blank or a single letter from A to Z. It
+// reports false if input ran out.
+func (g *Game) pickLetter(round *Round) bool {
+	for {
+		value, ok := g.ask(fmt.Sprintf(msg("pick-letter"), letterText(round.Letters)))
+		if !ok {
+			return false
+		}
+		if value == "" {
+			return true
+		}
+		if letter := unicode.ToUpper(rune(value[0])); len(value) == 1 && letter >= 'A' && letter <= 'Z' {
+			g.OverrideLetter(round, letter)
+			return true
+		}
+		fmt.Fprintf(g.out, msg("pick-bad")+"\n", value)
+	}
+}
+
+// snapshot saves the game's draw state so Undo can put it back.
+func (g *Game) snapshot() {
+	s := g.Session()
+	g.undo = &s
+	g.undoneTo = g.practiced
+}
+
+// Undo takes back the latest draw, restoring the pools, the previous round
+// and the random source to just before it, so the next draw comes out the
+// same. It reports false if there's nothing to take back.
+func (g *Game) Undo() bool {
+	if g.undo == nil {
+		return false
+	}
+	g.restore(*g.undo)
+	g.practiced = g.undoneTo
+	g.undo = nil
+	return true
+}
+
+// playoff settles a tie for first place with one more prompt, for a letter
+// and prompt drawn from the game's seed: the tied players answer it, it's
+// scored as usual, and the points go on the scoreboard's Playoff tally for
+// Standings to break the tie with.
+func (g *Game) playoff() {
+	standings := g.Board.Standings()
+	if len(standings) < 2 || standings[0].Points != standings[1].Points {
+		return
+	}
+	tied := []string{}
+	for _, standing := range standings {
+		if standing.Points == standings[0].Points {
+			tied = append(tied, standing.Player)
+		}
+	}
+	round := Round{Number: g.Played + 1, Letters: []rune{}, Prompts: []string{g.prompts[g.rng.Intn(len(g.prompts))]}}
+	if !g.NoLetter {
+		round.Letters = []rune{g.Letters[g.rng.Intn(len(g.Letters))]}
+	}
+	fmt.Fprintln(g.out, SEP)
+	names := strings.Join(tied[:len(tied)-1], ", ") + " " + msg("and") + " " + tied[len(tied)-1]
+	fmt.Fprintf(g.out, msg("tie")+"\n", names)
+	printLetters(g.out, round.Letters)
+	if !collectAnswers(g.out, &round, tied, g.lines) {
+		return
+	}
+	points := g.Scorer.Score(&round)
+	printScores(g.out, round, tied, points)
+	g.mu.Lock()
+	g.Board.Playoff = points
+	g.mu.Unlock()
+}
+
+// liveRound is the round being played, or the last one once its timer stops.
+type liveRound struct {
+	round *Round
+	timer *roundTimer // nil unless the timer is running
+}
+
+// Run plays rounds until input runs out or, with Rounds or MaxRounds set,
+// until that many have been played, then prints the end-of-game report. With
+// Rest set, each round after the first starts on its own once Rest has
+// passed.
+func (g *Game) Run(in *interrupter) {
+	defer func() {
+		if TIEBREAK == "prompt" && len(PLAYERS) > 0 && len(TEAMS) == 0 {
+			g.playoff()
+		}
+		g.Report()
+	}()
+	for g.more() {
+		// Show the menu, unless rounds run unattended
+		replay := false
+		if g.Rounds == 0 && g.Rest == 0 {
+			var ok bool
+			if replay, ok = g.Menu(); !ok {
+				return
+			}
+		}
+		if !g.LetterLast {
+			preRoundCountdown(in.startRound(), g.out, g.Clock, COUNTDOWN)
+			in.endRound()
+		}
+
+		// Draw and play the round, drawing again straight away if asked
+		config := g.Config
+		sudden := g.SuddenDeath && g.lastRound()
+		if sudden {
+			g.Duration /= 2
+			fmt.Fprintln(g.out, paint(COLOR_RED, msg("sudden-death")))
+		}
+		var round Round
+		if replay {
+			round, _ = g.ReplayRound()
+		} else {
+			round = g.NextRound()
+		}
+		round.SuddenDeath = sudden
+		if PICK_LETTER && !replay && len(g.Practice) == 0 && !g.pickLetter(&round) {
+			return
+		}
+		announceShuffle(g.out, round)
+		play := g.Play
+		if BUZZER {
+			play = g.PlayBuzzer
+		} else if TURNS {
+			play = g.PlayTurns
+		} else if SPRINT {
+			play = g.PlaySprint
+		}
+		slog.Info("round started", "round", round.Number, "letters", string(round.Letters), "replay", replay)
+		g.emitStart(round)
+		g.previewNext()
+		result := play(&round, in)
+		for result == REDRAWN {
+			g.Events.Emit(event{Type: "round_end", Time: g.Clock.Now(), Round: round.Number, Result: result.String()})
+			g.Redraw(round)
+			round = g.NextRound()
+			round.SuddenDeath = sudden
+			if PICK_LETTER && len(g.Practice) == 0 && !g.pickLetter(&round) {
+				return
+			}
+			announceShuffle(g.out, round)
+			slog.Info("round redrawn", "round", round.Number, "letters", string(round.Letters))
+			g.emitStart(round)
+			g.previewNext()
+			result = play(&round, in)
+		}
+		g.Config = config
+		slog.Info("round ended", "round", round.Number, "result", result)
+		g.Events.Emit(event{Type: "round_end", Time: g.Clock.Now(), Round: round.Number, Result: result.String()})
+		if result == QUIT {
+			return
+		}
+		if result == UNDONE {
+			g.Undo()
+			continue
+		}
+		g.notifyEnd(result)
+
+		// Score and record it
+		if VOTE && len(PLAYERS) > 0 && !voteOnAnswers(g.out, &round, PLAYERS, g.lines, VOTE_TIES_ACCEPT) {
+			return
+		}
+		var points map[string]int
+		if len(PLAYERS) > 0 {
+			scorer := g.Scorer
+			if round.SuddenDeath {
+				scorer = scaledScorer{scorer, 2}
+			}
+			points = scorer.Score(&round)
+			printScores(g.out, round, PLAYERS, points)
+			g.mu.Lock()
+			g.Board.AddRound(points)
+			g.Board.AddDuplicates(round.Answers)
+			g.Events.Emit(event{Type: "score_update", Time: g.Clock.Now(), Round: round.Number, Points: points, Totals: g.Board.Totals})
+			g.mu.Unlock()
+			g.Board.Print(g.out)
+			if STANDINGS_PATH != "" {
+				if err := g.saveStandings(); err != nil {
+					slog.Error("saving standings", "path", STANDINGS_PATH, "err", err)
+				}
+			}
+		}
+		g.recall = &round
+		if !NO_SUMMARY {
+			printSummary(g.out, round, PLAYERS)
+		}
+		if g.practicing != nil {
+			printPractice(g.out, round, *g.practicing)
+		}
+		g.saving.Lock()
+		if g.Exporter != nil {
+			if err := g.Exporter.WriteRound(round); err != nil {
+				slog.Error("exporting round", "round", round.Number, "err", err)
+			}
+		}
+		if ANSWERS_DIR != "" && len(PLAYERS) > 0 {
+			if path, err := writeAnswersFile(ANSWERS_DIR, round, PLAYERS, sessionInfo{TITLE, TITLE_DATE}); err != nil {
+				slog.Error("writing answers file", "dir", ANSWERS_DIR, "round", round.Number, "err", err)
+			} else {
+				slog.Info("wrote answers file", "path", path)
+			}
+		}
+		if g.History != nil {
+			if err := g.History.WriteRound(round, points, g.Clock.Now()); err != nil {
+				slog.Error("writing history", "round", round.Number, "err", err)
+			}
+		}
+		if g.Memory != nil {
+			g.Memory.Remember(round.Letters)
+			if err := g.Memory.Save(); err != nil {
+				slog.Error("saving letter memory", "path", g.Memory.path, "err", err)
+			}
+		}
+		if SESSION_PATH != "" {
+			if err := g.SaveSession(SESSION_PATH); err != nil {
+				slog.Error("saving session", "path", SESSION_PATH, "err", err)
+			}
+		}
+		g.saving.Unlock()
+		fmt.Fprintln(g.out, SEP)
+		if g.Rest > 0 && g.more() {
+			rest(g.out, g.Clock, g.Rest, g.lines)
+		}
+	}
+}
+
+// emitStart emits a round_start event for round.
+func (g *Game) emitStart(round Round) {
+	g.Events.Emit(event{Type: "round_start", Time: g.Clock.Now(), Round: round.Number, Letters: string(round.Letters), Prompts: round.Prompts})
+}
+
+// lastRound reports whether the next round is the last that Rounds and
+// MaxRounds allow.
+func (g *Game) lastRound() bool {
+	limit := g.Rounds
+	if g.MaxRounds > 0 && (limit == 0 || g.MaxRounds < limit) {
+		limit = g.MaxRounds
+	}
+	return limit > 0 && g.Played+1 == limit
+}
+
+// more reports whether Rounds and MaxRounds leave another round to play.
+func (g *Game) more() bool {
+	return (g.Rounds == 0 || g.Played < g.Rounds) && (g.MaxRounds == 0 || g.Played < g.MaxRounds) &&
+		(len(g.Practice) == 0 || PRACTICE_LOOP || g.practiced < len(g.Practice))
+}
+
+// notifyEnd sends a desktop notification that a round ended, in the
+// background so a slow notifier doesn't hold up scoring.
+func (g *Game) notifyEnd(result timerResult) {
+	if g.Notifier == nil {
+		return
+	}
+	message := msg("times-up")
+	if result != TIME_UP {
+		message = msg("ended-early")
+	}
+	go func() {
+		if err := g.Notifier.Notify("Scattergories", message); err != nil {
+			slog.Debug("desktop notification failed", "err", err)
+		}
+	}()
+}
+
+// RemainingLetters returns the letters left before the letter pool reshuffles.
+func (g *Game) RemainingLetters() []rune {
+	return append([]rune{}, g.letterPool...)
+}
+
+// RemainingPrompts returns how many prompts are left before the prompt pool
+// reshuffles.
+func (g *Game) RemainingPrompts() int {
+	return len(g.promptPool)
+}
+
+// shuffled returns a shuffled copy of items, leaving items untouched.
+func shuffled[T any](rng *rand.Rand, items []T) []T {
+	out := append([]T{}, items...)
+	rng.Shuffle(len(out), func(i, j int) {
+		out[i], out[j] = out[j], out[i]
+	})
+	return out
+}
+
+// draw takes n items off the front of pool. If pool can't fill the request,
+// it's replaced with a fresh shuffle of all before drawing, with any items in
+// prev (the last draw) moved to the back so a new cycle doesn't open with a
+// repeat unless there's nothing else to draw.
+func draw[T comparable](rng *rand.Rand, all, pool []T, n int, prev []T) (drawn, rest []T) {
+	if len(pool) < n {
+		fresh, repeats := []T{}, []T{}
+		for _, item := range shuffled(rng, all) {
+			if slices.Contains(prev, item) {
+				repeats = append(repeats, item)
+			} else {
+				fresh = append(fresh, item)
+			}
+		}
+		pool = append(fresh, repeats...)
+	}
+	return pool[:n], pool[n:]
+}
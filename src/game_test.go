@@ -0,0 +1,739 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var TEST_PROMPTS = []string{"Animals", "Bands", "Cars", "Desserts", "Elements", "Fruits", "Games"}
+
+// newTestGame makes a game over prompts with a fixed seed, no input and its
+// output thrown away.
+func newTestGame(config Config, prompts []string) *Game {
+	return NewGame(config, prompts, 1, nil, io.Discard)
+}
+
+func TestNextRoundRefillsExhaustedPrompts(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 3, Letters: []rune("ABC")}, TEST_PROMPTS)
+	for i := 0; i < 5*len(TEST_PROMPTS); i++ {
+		round := g.NextRound()
+		if len(round.Prompts) != 3 {
+			t.Fatalf("round %d drew %d prompts, want 3", round.Number, len(round.Prompts))
+		}
+		for j, prompt := range round.Prompts {
+			if !slices.Contains(TEST_PROMPTS, prompt) {
+				t.Fatalf("round %d drew unknown prompt %q", round.Number, prompt)
+			}
+			if slices.Contains(round.Prompts[:j], prompt) {
+				t.Fatalf("round %d drew %q twice: %v", round.Number, prompt, round.Prompts)
+			}
+		}
+	}
+}
+
+func TestNextRoundUsesEveryPromptBeforeReshuffling(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 2, Letters: []rune("A")}, TEST_PROMPTS[:6])
+	seen := []string{}
+	for i := 0; i < 3; i++ {
+		round := g.NextRound()
+		seen = append(seen, round.Prompts...)
+	}
+	want := slices.Clone(TEST_PROMPTS[:6])
+	slices.Sort(seen)
+	slices.Sort(want)
+	if !slices.Equal(seen, want) {
+		t.Errorf("first cycle drew %v, want each of %v once", seen, want)
+	}
+	if left := g.RemainingPrompts(); left != 0 {
+		t.Errorf("%d prompts left after the first cycle, want none", left)
+	}
+}
+
+func TestNextRoundCyclesLettersDeterministically(t *testing.T) {
+	letters := []rune("ABCDEFGHIJKLMNOPRSTW")
+	draws := func() []rune {
+		g := newTestGame(Config{NumPrompts: 1, Letters: letters}, TEST_PROMPTS)
+		drawn := []rune{}
+		for i := 0; i < 25; i++ {
+			round := g.NextRound()
+			if len(round.Letters) != 1 {
+				t.Fatalf("round %d drew letters %q, want one", round.Number, string(round.Letters))
+			}
+			drawn = append(drawn, round.Letters[0])
+		}
+		return drawn
+	}
+	drawn := draws()
+	cycle := slices.Clone(drawn[:len(letters)])
+	slices.Sort(cycle)
+	if !slices.Equal(cycle, letters) {
+		t.Errorf("first 20 rounds drew %q, want every letter once", string(drawn[:len(letters)]))
+	}
+	for i, letter := range drawn[len(letters):] {
+		if !slices.Contains(letters, letter) || slices.Contains(drawn[len(letters):len(letters)+i], letter) {
+			t.Errorf("round %d drew %q again before the new cycle ran out", len(letters)+i+1, letter)
+		}
+	}
+	if again := draws(); !slices.Equal(again, drawn) {
+		t.Errorf("same seed drew %q, then %q", string(drawn), string(again))
+	}
+}
+
+func TestNextRoundRepeatLetters(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 1, Letters: []rune("AB"), RepeatLetters: true}, TEST_PROMPTS)
+	repeated := false
+	last := g.NextRound().Letters[0]
+	for i := 0; i < 25; i++ {
+		letter := g.NextRound().Letters[0]
+		if letter != 'A' && letter != 'B' {
+			t.Fatalf("round %d drew %q, want A or B", i+2, letter)
+		}
+		repeated = repeated || letter == last
+		last = letter
+	}
+	if !repeated {
+		t.Error("25 independent draws from two letters never repeated one straight away")
+	}
+}
+
+func TestSameSeedSameRounds(t *testing.T) {
+	rounds := func(seed int64) []Round {
+		g := NewGame(Config{NumPrompts: 3, Letters: LETTERS}, TEST_PROMPTS, seed, nil, io.Discard)
+		drawn := []Round{}
+		for i := 0; i < 10; i++ {
+			drawn = append(drawn, g.NextRound())
+		}
+		return drawn
+	}
+	first, second, other := rounds(42), rounds(42), rounds(43)
+	same := func(a, b []Round) bool {
+		return slices.EqualFunc(a, b, func(x, y Round) bool {
+			return slices.Equal(x.Letters, y.Letters) && slices.Equal(x.Prompts, y.Prompts)
+		})
+	}
+	if !same(first, second) {
+		t.Error("two games with seed 42 drew different rounds")
+	}
+	if same(first, other) {
+		t.Error("seeds 42 and 43 drew the same rounds")
+	}
+}
+
+func TestGameRoundsAndPools(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		config     Config
+		letters    int // drawn per round
+		prompts    int
+		letterLeft int // left in the pool after one round
+	}{
+		{"one letter", Config{NumPrompts: 3, Letters: []rune("ABCD")}, 1, 3, 3},
+		{"two letters", Config{NumPrompts: 2, Letters: []rune("ABCD"), LettersPer: 2}, 2, 2, 2},
+		{"no letter", Config{NumPrompts: 4, Letters: []rune("ABCD"), NoLetter: true}, 0, 4, 4},
+		{"every prompt", Config{NumPrompts: len(TEST_PROMPTS), Letters: []rune("AB")}, 1, len(TEST_PROMPTS), 1},
+	} {
+		g := newTestGame(test.config, TEST_PROMPTS)
+		if left := g.RemainingPrompts(); left != len(TEST_PROMPTS) {
+			t.Errorf("%s: %d prompts in a new game's pool, want %d", test.name, left, len(TEST_PROMPTS))
+		}
+		round := g.NextRound()
+		if len(round.Letters) != test.letters || len(round.Prompts) != test.prompts {
+			t.Errorf("%s: drew %d letters and %d prompts, want %d and %d", test.name, len(round.Letters), len(round.Prompts), test.letters, test.prompts)
+		}
+		if left := g.RemainingLetters(); len(left) != test.letterLeft {
+			t.Errorf("%s: %d letters left, want %d", test.name, len(left), test.letterLeft)
+		}
+		for _, letter := range round.Letters {
+			if slices.Contains(g.RemainingLetters(), letter) {
+				t.Errorf("%s: drawn letter %c is still in the pool", test.name, letter)
+			}
+		}
+		if left := g.RemainingPrompts(); left != len(TEST_PROMPTS)-test.prompts {
+			t.Errorf("%s: %d prompts left, want %d", test.name, left, len(TEST_PROMPTS)-test.prompts)
+		}
+		g.Reshuffle()
+		if len(g.RemainingLetters()) != len(test.config.Letters) || g.RemainingPrompts() != len(TEST_PROMPTS) {
+			t.Errorf("%s: Reshuffle left %d letters and %d prompts", test.name, len(g.RemainingLetters()), g.RemainingPrompts())
+		}
+	}
+}
+
+func TestDrawKeepsTheLastDrawBack(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	all := []rune("ABCDE")
+	drawn, rest := draw(rng, all, []rune("A"), 2, []rune("CD"))
+	if len(drawn) != 2 || slices.Contains(drawn, 'C') || slices.Contains(drawn, 'D') {
+		t.Errorf("drew %q from a fresh pool, want neither of the last draw's C and D", string(drawn))
+	}
+	if len(rest) != 3 || !slices.Contains(rest[1:], 'C') || !slices.Contains(rest[1:], 'D') {
+		t.Errorf("left %q, want C and D at the back", string(rest))
+	}
+	if drawn, rest := draw(rng, all, []rune("BE"), 2, nil); string(drawn) != "BE" || len(rest) != 0 {
+		t.Errorf("drew %q leaving %q from a pool that could fill the draw", string(drawn), string(rest))
+	}
+}
+
+func TestRunPlaysUnattendedRounds(t *testing.T) {
+	var out bytes.Buffer
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("AB"), Resolution: time.Second, Rounds: 3}, TEST_PROMPTS, 1, nil, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.Run(&interrupter{out: &out})
+	if g.Played != 3 || g.Stats.Rounds != 3 {
+		t.Errorf("played %d rounds (%d in the stats), want 3", g.Played, g.Stats.Rounds)
+	}
+	if n := strings.Count(out.String(), "Time's up!"); n != 3 {
+		t.Errorf("%d rounds ran out of time, want 3:\n%s", n, out.String())
+	}
+	if g.Stats.TimePlayed != 3*time.Minute {
+		t.Errorf("played for %s, want 3m", g.Stats.TimePlayed)
+	}
+}
+
+func TestRedrawReturnsTheRoundToThePools(t *testing.T) {
+	for _, discard := range []bool{false, true} {
+		set(t, &DISCARD_REDRAWN, discard)
+		g := newTestGame(Config{NumPrompts: 2, Letters: []rune("ABC")}, TEST_PROMPTS[:4])
+		skipped := g.NextRound()
+		g.Redraw(skipped)
+		if g.Played != 0 {
+			t.Errorf("discard %v: %d rounds played after redrawing the first, want 0", discard, g.Played)
+		}
+		wantPrompts, wantLetters := 4, 3
+		if discard {
+			wantPrompts, wantLetters = 2, 2
+		}
+		if g.RemainingPrompts() != wantPrompts || len(g.RemainingLetters()) != wantLetters {
+			t.Errorf("discard %v: %d prompts and %d letters left, want %d and %d", discard, g.RemainingPrompts(), len(g.RemainingLetters()), wantPrompts, wantLetters)
+		}
+		// Without discarding, the skipped prompts come up again before the
+		// pool runs out; with it, the other two fill the next round.
+		seen := []string{}
+		for g.RemainingPrompts() > 0 {
+			seen = append(seen, g.NextRound().Prompts...)
+		}
+		for _, prompt := range skipped.Prompts {
+			if slices.Contains(seen, prompt) == discard {
+				t.Errorf("discard %v: skipped prompt %q came up again %v", discard, prompt, !discard)
+			}
+		}
+	}
+}
+
+func TestRunRedrawsARound(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	// Start a round, redraw it, end the new one early, then quit
+	script := "1\n" + REDRAW_KEY + "\n" + SKIP_KEY + "\n5\n"
+	var out bytes.Buffer
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("AB"), Resolution: time.Second}, TEST_PROMPTS, 1, readLines(strings.NewReader(script)), &out)
+	g.Clock = newFakeClock()
+	g.Run(&interrupter{out: &out})
+	if g.Played != 1 || g.Stats.Rounds != 1 || g.Stats.Redrawn != 1 {
+		t.Errorf("played %d rounds (%d in the stats, %d redrawn), want one redrawn, then one played", g.Played, g.Stats.Rounds, g.Stats.Redrawn)
+	}
+	if strings.Count(out.String(), SEP+"\nLetter: ") != 2 || !strings.Contains(out.String(), msg("redrawing")) {
+		t.Errorf("didn't redraw the round:\n%s", out.String())
+	}
+}
+
+func TestReplayRound(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 3, Letters: []rune("ABC")}, TEST_PROMPTS[:6])
+	if _, ok := g.ReplayRound(); ok {
+		t.Error("replayed a round before any was drawn")
+	}
+	g.NextRound()
+	// The second round empties the prompt pool, so the next draw reshuffles
+	drawn := g.NextRound()
+	letters, prompts := len(g.RemainingLetters()), g.RemainingPrompts()
+	replay, ok := g.ReplayRound()
+	if !ok || !slices.Equal(replay.Letters, drawn.Letters) || !slices.Equal(replay.Prompts, drawn.Prompts) {
+		t.Fatalf("replay drew %q %v, want %q %v", string(replay.Letters), replay.Prompts, string(drawn.Letters), drawn.Prompts)
+	}
+	if replay.Number != 3 || !replay.Replay {
+		t.Errorf("replay is round %d, marked replay %v; want round 3, marked", replay.Number, replay.Replay)
+	}
+	if len(g.RemainingLetters()) != letters || g.RemainingPrompts() != prompts {
+		t.Errorf("replaying took from the pools: %d letters and %d prompts left, want %d and %d", len(g.RemainingLetters()), g.RemainingPrompts(), letters, prompts)
+	}
+	reshuffled := g.NextRound()
+	again, _ := g.ReplayRound()
+	if !slices.Equal(again.Letters, reshuffled.Letters) || !slices.Equal(again.Prompts, reshuffled.Prompts) {
+		t.Errorf("replay after a reshuffle drew %q %v, want %q %v", string(again.Letters), again.Prompts, string(reshuffled.Letters), reshuffled.Prompts)
+	}
+}
+
+func TestNoRepeatsAcrossReshuffles(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		g := NewGame(Config{NumPrompts: 2, Letters: []rune("ABC")}, TEST_PROMPTS[:5], seed, nil, io.Discard)
+		last := g.NextRound()
+		for i := 0; i < 30; i++ {
+			round := g.NextRound()
+			if slices.Equal(round.Letters, last.Letters) {
+				t.Fatalf("seed %d: rounds %d and %d both drew %q", seed, last.Number, round.Number, string(round.Letters))
+			}
+			for _, prompt := range round.Prompts {
+				if slices.Contains(last.Prompts, prompt) {
+					t.Fatalf("seed %d: rounds %d and %d both drew %q", seed, last.Number, round.Number, prompt)
+				}
+			}
+			last = round
+		}
+	}
+}
+
+func TestOneLetterRepeats(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 1, Letters: []rune("A")}, TEST_PROMPTS)
+	for i := 0; i < 3; i++ {
+		if round := g.NextRound(); string(round.Letters) != "A" {
+			t.Fatalf("round %d drew %q from just A", round.Number, string(round.Letters))
+		}
+	}
+}
+
+func TestAutoAdvance(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	var out bytes.Buffer
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("AB"), Resolution: time.Second, Rest: 15 * time.Second, Rounds: 3}, TEST_PROMPTS, 1, nil, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	start := clock.Now()
+	g.Clock = clock
+	g.Run(&interrupter{out: &out})
+	if g.Played != 3 {
+		t.Errorf("played %d rounds, want 3", g.Played)
+	}
+	// Rests come between rounds, not after the last
+	if took, want := clock.Now().Sub(start), 3*time.Minute+2*15*time.Second; took != want {
+		t.Errorf("game took %s, want %s", took, want)
+	}
+	if strings.Contains(out.String(), msg("menu")) {
+		t.Errorf("auto mode showed the menu:\n%s", out.String())
+	}
+	if n := strings.Count(out.String(), fmt.Sprintf(msg("rest"), "0m15s")); n != 2 {
+		t.Errorf("started %d rests, want 2:\n%s", n, out.String())
+	}
+}
+
+func TestRestCountsDown(t *testing.T) {
+	clock := newFakeClock()
+	clock.auto = true
+	var out bytes.Buffer
+	rest(&out, clock, 3*time.Second, nil)
+	for _, left := range []string{"0m3s", "0m2s", "0m1s"} {
+		if !strings.Contains(out.String(), "\r"+fmt.Sprintf(msg("rest"), left)) {
+			t.Errorf("rest didn't count down through %s:\n%q", left, out.String())
+		}
+	}
+	if strings.Count(out.String(), "\r") != 3 || !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("rest wrote %q, want three updates then a new line", out.String())
+	}
+}
+
+func TestMaxRoundsStopsAtTheMenu(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	// Enough input for a third round, which shouldn't start
+	script := strings.Repeat("1\n"+SKIP_KEY+"\n", 3)
+	g, out := scriptedGame(Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("AB"), Resolution: time.Second, MaxRounds: 2}, script)
+	g.Clock = newFakeClock()
+	g.Run(&interrupter{out: out})
+	if g.Played != 2 {
+		t.Errorf("played %d rounds, want 2", g.Played)
+	}
+	if n := strings.Count(out.String(), msg("menu")); n != 2 {
+		t.Errorf("showed the menu %d times, want once before each round", n)
+	}
+	if !strings.HasSuffix(out.String(), SEP+"\n") || !strings.Contains(out.String(), msg("game-over")) {
+		t.Errorf("didn't end with the report:\n%s", out.String())
+	}
+}
+
+func TestUndoDrawsTheSameRoundAgain(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 2, Letters: []rune("ABC")}, TEST_PROMPTS[:4])
+	if g.Undo() {
+		t.Error("undid a draw before any was made")
+	}
+	// The third round reshuffles both pools, so undo has to put the random
+	// source back too
+	for i := 1; i <= 3; i++ {
+		drawn := g.NextRound()
+		letters, prompts := len(g.RemainingLetters()), g.RemainingPrompts()
+		if !g.Undo() {
+			t.Fatalf("round %d: nothing to undo", i)
+		}
+		if g.Undo() {
+			t.Errorf("round %d: undid the same draw twice", i)
+		}
+		again := g.NextRound()
+		if again.Number != drawn.Number || !slices.Equal(again.Letters, drawn.Letters) || !slices.Equal(again.Prompts, drawn.Prompts) {
+			t.Errorf("round %d: drew %d %q %v after undoing, want %d %q %v", i, again.Number, string(again.Letters), again.Prompts, drawn.Number, string(drawn.Letters), drawn.Prompts)
+		}
+		if len(g.RemainingLetters()) != letters || g.RemainingPrompts() != prompts {
+			t.Errorf("round %d: %d letters and %d prompts left after undoing, want %d and %d", i, len(g.RemainingLetters()), g.RemainingPrompts(), letters, prompts)
+		}
+	}
+}
+
+func TestRunUndoesARound(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	// Start a round, take it back, start another and end it, then quit
+	script := "1\n" + UNDO_KEY + "\n1\n" + SKIP_KEY + "\n5\n"
+	config := Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("ABC"), Resolution: time.Second}
+	g, out := scriptedGame(config, script)
+	g.Clock = newFakeClock()
+	g.Run(&interrupter{out: out})
+	if g.Played != 1 || g.Stats.Rounds != 1 {
+		t.Errorf("played %d rounds (%d in the stats), want the undone one not to count", g.Played, g.Stats.Rounds)
+	}
+	if !strings.Contains(out.String(), msg("undone")) {
+		t.Errorf("didn't undo the round:\n%s", out.String())
+	}
+	want := NewGame(config, TEST_PROMPTS, 1, nil, io.Discard).NextRound()
+	rounds := strings.Split(out.String(), SEP+"\nLetter: ")[1:]
+	if len(rounds) != 2 {
+		t.Fatalf("started %d rounds, want 2:\n%s", len(rounds), out.String())
+	}
+	for i, round := range rounds {
+		if !strings.HasPrefix(round, letterText(want.Letters)) {
+			t.Errorf("round %d drew %q, want %q", i+1, strings.SplitN(round, "\n", 2)[0], letterText(want.Letters))
+		}
+		for _, prompt := range want.Prompts {
+			if !strings.Contains(strings.Split(round, msg("undone"))[0], prompt) {
+				t.Errorf("round %d is missing prompt %q", i+1, prompt)
+			}
+		}
+	}
+}
+
+func TestSuddenDeathIsTheLastRoundOnly(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	set(t, &PLAYERS, []string{"Al"})
+	// Three rounds, each ended early and answered
+	script := strings.Repeat(SKIP_KEY+"\nAnt\n", 3)
+	config := Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("A"), RepeatLetters: true, Resolution: time.Second, Rounds: 3, SuddenDeath: true}
+	g, out := scriptedGame(config, script)
+	g.Clock = newFakeClock()
+	var stream bytes.Buffer
+	g.Events = newEventStream(&stream)
+	g.Run(&interrupter{out: out})
+	if g.Played != 3 {
+		t.Fatalf("played %d rounds, want 3:\n%s", g.Played, out.String())
+	}
+	if n := strings.Count(out.String(), msg("sudden-death")); n != 1 {
+		t.Errorf("announced sudden death %d times, want once", n)
+	}
+	if g.Duration != time.Minute {
+		t.Errorf("rounds are %s long after sudden death, want 1m again", g.Duration)
+	}
+
+	// Each round's first tick shows its length, and its score its points
+	lengths, points := map[int]int{}, map[int]int{}
+	for _, e := range readEvents(t, stream.String()) {
+		switch e.Type {
+		case "tick":
+			if _, ok := lengths[e.Round]; !ok {
+				lengths[e.Round] = *e.Remaining
+			}
+		case "score_update":
+			points[e.Round] = e.Points["Al"]
+		}
+	}
+	for round, want := range map[int][2]int{1: {60, 1}, 2: {60, 1}, 3: {30, 2}} {
+		if lengths[round] != want[0] || points[round] != want[1] {
+			t.Errorf("round %d was %ds for %d points, want %ds for %d", round, lengths[round], points[round], want[0], want[1])
+		}
+	}
+	if g.Board.Totals["Al"] != 4 {
+		t.Errorf("Al has %d points in the standings, want 4 with the last round doubled", g.Board.Totals["Al"])
+	}
+}
+
+func TestPlanMatchesARun(t *testing.T) {
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("ABC"), Resolution: time.Second, Rounds: 5}, TEST_PROMPTS[:5], 7, nil, io.Discard)
+	var plan, again bytes.Buffer
+	g.Plan(&plan, 5)
+	g.Plan(&again, 5)
+	if plan.String() != again.String() {
+		t.Fatalf("planned\n%s\nthen\n%s", plan.String(), again.String())
+	}
+	if len(g.RemainingLetters()) != 3 || g.RemainingPrompts() != 5 || g.Played != 0 {
+		t.Errorf("planning drew from the game: %d letters and %d prompts left, %d played", len(g.RemainingLetters()), g.RemainingPrompts(), g.Played)
+	}
+
+	// Play the rounds for real, writing them down the way Plan does
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	var stream bytes.Buffer
+	g.Events = newEventStream(&stream)
+	g.Run(&interrupter{out: io.Discard})
+	var played strings.Builder
+	for _, e := range readEvents(t, stream.String()) {
+		if e.Type != "round_start" {
+			continue
+		}
+		fmt.Fprintf(&played, "Round %d: %s\n", e.Round, letterText([]rune(e.Letters)))
+		for i, prompt := range e.Prompts {
+			fmt.Fprintf(&played, "  %d.\t%s\n", i+1, prompt)
+		}
+	}
+	if played.String() != plan.String() {
+		t.Errorf("planned\n%s\nplayed\n%s", plan.String(), played.String())
+	}
+}
+
+func TestNoLetter(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 2, Letters: []rune("ABC"), NoLetter: true}, TEST_PROMPTS[:4])
+	pool := string(g.RemainingLetters())
+	for i := 1; i <= 5; i++ {
+		round := g.NextRound()
+		if len(round.Letters) != 0 || round.LettersShuffled {
+			t.Errorf("round %d drew %q, want no letter", i, string(round.Letters))
+		}
+		if want := i == 3 || i == 5; round.PromptsShuffled != want {
+			t.Errorf("round %d reshuffled the prompts %v, want %v", i, round.PromptsShuffled, want)
+		}
+		if answer := newAnswer(&round, "Al", 0, "Zebra"); !answer.Valid {
+			t.Errorf("round %d rejected %q: %s", i, answer.Text, answer.Reason)
+		}
+	}
+	if string(g.RemainingLetters()) != pool {
+		t.Errorf("letter pool went from %q to %q, want it untouched", pool, string(g.RemainingLetters()))
+	}
+
+	set(t, &QUIET, false)
+	set(t, &BELLS, 0)
+	round, out := playTestRound(t, Config{Duration: 3 * time.Second, NumPrompts: 2, Letters: []rune("ABC"), NoLetter: true, Resolution: time.Second})
+	if strings.Contains(out, "Letter") || strings.Contains(out, "\n\n\n") {
+		t.Errorf("showed a letter line, or the gap where one was:\n%s", out)
+	}
+	for _, prompt := range round.Prompts {
+		if !strings.Contains(out, prompt) {
+			t.Errorf("didn't show prompt %q:\n%s", prompt, out)
+		}
+	}
+}
+
+func TestPromptCountRange(t *testing.T) {
+	prompts := []string{}
+	for i := 0; i < 30; i++ {
+		prompts = append(prompts, fmt.Sprintf("Prompt %d", i))
+	}
+	for _, test := range []struct {
+		prompts    []string
+		lo, hi     int
+		wantCounts []int
+	}{
+		{prompts, 2, 5, []int{2, 3, 4, 5}},
+		{prompts, 4, 4, []int{4}},
+		{TEST_PROMPTS, 5, 10, []int{5, 6, 7}}, // never more than there are
+	} {
+		g := newTestGame(Config{NumPrompts: test.lo, MaxPrompts: test.hi, Letters: []rune("AB")}, test.prompts)
+		counts := map[int]bool{}
+		for i := 0; i < 200; i++ {
+			round := g.NextRound()
+			counts[len(round.Prompts)] = true
+			if n := len(round.Prompts); n < test.lo || n > min(test.hi, len(test.prompts)) {
+				t.Fatalf("%d-%d of %d: round %d drew %d prompts", test.lo, test.hi, len(test.prompts), round.Number, n)
+			}
+		}
+		for _, n := range test.wantCounts {
+			if !counts[n] {
+				t.Errorf("%d-%d of %d: no round drew %d prompts in 200", test.lo, test.hi, len(test.prompts), n)
+			}
+		}
+	}
+}
+
+func TestOverrideLetter(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 1, Letters: []rune("ABCDE")}, TEST_PROMPTS)
+	round := g.NextRound()
+	drawn := round.Letters[0]
+	pick := 'A'
+	if drawn == pick {
+		pick = 'B'
+	}
+	g.OverrideLetter(&round, pick)
+	if string(round.Letters) != string(pick) {
+		t.Errorf("round plays %q, want %q", string(round.Letters), string(pick))
+	}
+	pool := g.RemainingLetters()
+	if len(pool) != 4 || !slices.Contains(pool, drawn) || slices.Contains(pool, pick) {
+		t.Errorf("pool is %q after picking %q over %q, want the drawn letter back and the picked one out", string(pool), string(pick), string(drawn))
+	}
+	// The pool's four letters come next, each once, before it runs out
+	seen := []rune{}
+	for range 4 {
+		next := g.NextRound()
+		if next.LettersShuffled || slices.Contains(seen, next.Letters[0]) || next.Letters[0] == pick {
+			t.Errorf("drew %q after %q, want each of %q once", string(next.Letters), string(seen), string(pool))
+		}
+		seen = append(seen, next.Letters[0])
+	}
+
+	// A letter from outside the set is played without touching the pool
+	g = newTestGame(Config{NumPrompts: 1, Letters: []rune("ABC")}, TEST_PROMPTS)
+	round = g.NextRound()
+	g.OverrideLetter(&round, 'Z')
+	if len(g.RemainingLetters()) != 3 || slices.Contains(g.RemainingLetters(), 'Z') {
+		t.Errorf("pool is %q after picking Z, want all of ABC", string(g.RemainingLetters()))
+	}
+}
+
+func TestPickLetter(t *testing.T) {
+	for _, test := range []struct {
+		name, script string
+		ok           bool
+		bad          int
+	}{
+		{"keeps the draw", "\n", true, 0},
+		{"picks", "q\n", true, 0},
+		{"asks again", "QQ\n7\né\nq\n", true, 3},
+		{"input runs out", "zz\n", false, 1},
+	} {
+		g, out := scriptedGame(Config{NumPrompts: 1, Letters: []rune("ABC")}, test.script)
+		round := g.NextRound()
+		drawn := string(round.Letters)
+		if ok := g.pickLetter(&round); ok != test.ok {
+			t.Errorf("%s: pickLetter = %v, want %v", test.name, ok, test.ok)
+		}
+		want := "Q"
+		if test.script == "\n" {
+			want = drawn
+		}
+		if test.ok && string(round.Letters) != want {
+			t.Errorf("%s: round plays %q, want %q", test.name, string(round.Letters), want)
+		}
+		if n := strings.Count(out.String(), strings.TrimPrefix(msg("pick-bad"), "%q")); n != test.bad {
+			t.Errorf("%s: turned down %d answers, want %d:\n%s", test.name, n, test.bad, out.String())
+		}
+	}
+}
+
+func TestBlitz(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	set(t, &NO_SUMMARY, true)
+	set(t, &PLAYERS, []string{"Al", "Bo"})
+	// Bo misses the first three rounds, then they both score every round
+	script := ""
+	for i := 1; i <= 10; i++ {
+		bo := "Ape"
+		if i <= 3 {
+			bo = ""
+		}
+		script += SKIP_KEY + "\nAnt\n" + bo + "\n"
+	}
+	g, out := scriptedGame(Config{Duration: 30 * time.Second, NumPrompts: 1, Letters: []rune("A"), RepeatLetters: true, Resolution: time.Second, Rounds: 10}, script)
+	g.Clock = newFakeClock()
+	g.Run(&interrupter{out: out})
+	if g.Played != 10 || g.Stats.Rounds != 10 {
+		t.Errorf("played %d rounds (%d in the stats), want 10", g.Played, g.Stats.Rounds)
+	}
+	if strings.Contains(out.String(), msg("menu")) {
+		t.Errorf("stopped at the menu between rounds:\n%s", out.String())
+	}
+	if g.Board.Totals["Al"] != 10 || g.Board.Totals["Bo"] != 7 {
+		t.Errorf("standings are %v, want Al on 10 and Bo on 7", g.Board.Totals)
+	}
+	if standings := g.Board.Standings(); len(standings) != 2 || standings[0].Player != "Al" {
+		t.Errorf("final standings %+v, want Al first", standings)
+	}
+}
+
+func TestPlayoff(t *testing.T) {
+	set(t, &TIEBREAK, "prompt")
+	set(t, &QUIET, true)
+	set(t, &PLAYERS, []string{"Al", "Bo", "Cy"})
+	// Bo answers the playoff prompt and Al doesn't; Cy isn't tied for first
+	g, out := scriptedGame(Config{NumPrompts: 1, Letters: []rune("A")}, "\nAnt\n")
+	g.Board.Totals = map[string]int{"Al": 4, "Bo": 4, "Cy": 2}
+	g.playoff()
+	if !strings.Contains(out.String(), fmt.Sprintf(msg("tie"), "Al "+msg("and")+" Bo")) || strings.Contains(out.String(), "Cy") {
+		t.Errorf("didn't hold a playoff between Al and Bo alone:\n%s", out.String())
+	}
+	if standings := g.Board.Standings(); standings[0].Player != "Bo" || standings[0].Points != 4 || standings[1].Player != "Al" {
+		t.Errorf("standings %+v, want Bo ahead of Al on the same points", standings)
+	}
+
+	// Without a tie for first there's nothing to play off
+	g, out = scriptedGame(Config{NumPrompts: 1, Letters: []rune("A")}, "")
+	g.Board.Totals = map[string]int{"Al": 5, "Bo": 4, "Cy": 4}
+	g.playoff()
+	if out.Len() != 0 || g.Board.Playoff != nil {
+		t.Errorf("held a playoff without a tie for first:\n%s", out.String())
+	}
+}
+
+func TestHostPreviewMatchesTheNextDraw(t *testing.T) {
+	// Six rounds from three letters, two at a time, reshuffle along the way
+	var out, preview, stream bytes.Buffer
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("ABC"), LettersPer: 2, Resolution: time.Second, Rounds: 6}, TEST_PROMPTS, 5, nil, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.Preview = &preview
+	g.Events = newEventStream(&stream)
+	g.Run(&interrupter{out: &out})
+
+	drawn := []string{}
+	for _, e := range readEvents(t, stream.String()) {
+		if e.Type == "round_start" {
+			drawn = append(drawn, fmt.Sprintf("Host preview: round %d will be %s", e.Round, letterText([]rune(e.Letters))))
+		}
+	}
+	// Every round but the first is previewed during the one before
+	if want := strings.Join(drawn[1:], "\n") + "\n"; preview.String() != want {
+		t.Errorf("previewed\n%s\nthen drew\n%s", preview.String(), want)
+	}
+	if strings.Contains(out.String(), "Host preview") {
+		t.Errorf("showed the players the preview:\n%s", out.String())
+	}
+}
+
+func TestHostPreviewAfterARedraw(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	// Redraw the first round, end it, then play a second
+	script := "1\n" + REDRAW_KEY + "\n" + SKIP_KEY + "\n1\n" + SKIP_KEY + "\n5\n"
+	g, out := scriptedGame(Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("ABCDE"), Resolution: time.Second}, script)
+	g.Clock = newFakeClock()
+	var preview, stream bytes.Buffer
+	g.Preview = &preview
+	g.Events = newEventStream(&stream)
+	g.Run(&interrupter{out: out})
+	letters := []string{}
+	for _, e := range readEvents(t, stream.String()) {
+		if e.Type == "round_start" {
+			letters = append(letters, e.Letters)
+		}
+	}
+	previews := strings.Split(strings.TrimSuffix(preview.String(), "\n"), "\n")
+	if len(letters) != 3 || len(previews) != 3 {
+		t.Fatalf("drew %q and previewed\n%s\nwant three draws and three previews", letters, preview.String())
+	}
+	// The preview shown after the redraw, and after the second round started,
+	// hold; the first, made before the redraw took a letter, needn't
+	for i, want := range []string{"round 2 will be " + letters[2], "round 3 will be "} {
+		if !strings.Contains(previews[i+1], want) {
+			t.Errorf("preview %d is %q, want %q", i+2, previews[i+1], want)
+		}
+	}
+}
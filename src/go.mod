@@ -0,0 +1,3 @@
+module scattergories
+
+go 1.22
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// NO_EXAMPLE is the hint for a prompt with no example answers.
+const NO_EXAMPLE = "(no example)"
+
+// Examples maps prompts, lowercased, to example answers for them.
+type Examples map[string][]string
+
+// loadExamples reads example answers written one prompt per line as
+// "Things in a kitchen|Apron,Blender,Colander". Blank lines and lines
+// starting with # are skipped.
+func loadExamples(path string) (Examples, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	examples := Examples{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prompt, answers, _ := strings.Cut(line, "|")
+		key := strings.ToLower(strings.TrimSpace(prompt))
+		examples[key] = append(examples[key], splitList(answers)...)
+	}
+	return examples, scanner.Err()
+}
+
+// Hint suggests an answer to prompt: the first example starting with one of
+// letters, or failing that the first for any letter, or NO_EXAMPLE.
+func (e Examples) Hint(prompt string, letters []rune) string {
+	answers := e[strings.ToLower(strings.TrimSpace(prompt))]
+	for _, answer := range answers {
+		if validateAnswer(answer, letters, SKIP_ARTICLES).Valid {
+			return answer
+		}
+	}
+	if len(answers) > 0 {
+		return answers[0] + " (any letter)"
+	}
+	return NO_EXAMPLE
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const SAMPLE_EXAMPLES = `# prompt|answers
+Things in a kitchen|Apron, Blender,Colander
+Animals|Bear
+animals |Cat
+`
+
+func TestHint(t *testing.T) {
+	set(t, &SKIP_ARTICLES, false)
+	examples, err := loadExamples(writeTestFile(t, "examples.txt", SAMPLE_EXAMPLES))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		prompt, letters, want string
+	}{
+		{"Things in a kitchen", "B", "Blender"},
+		{"things in a KITCHEN ", "c", "Colander"},
+		{"Things in a kitchen", "AC", "Apron"},
+		{"Animals", "C", "Cat"},
+		{"Things in a kitchen", "Z", "Apron (any letter)"},
+		{"Animals", "", "Bear"},
+		{"Bands", "B", NO_EXAMPLE},
+	} {
+		if got := examples.Hint(test.prompt, []rune(test.letters)); got != test.want {
+			t.Errorf("Hint(%q, %q) = %q, want %q", test.prompt, test.letters, got, test.want)
+		}
+	}
+	if got := Examples(nil).Hint("Animals", []rune("B")); got != NO_EXAMPLE {
+		t.Errorf("Hint without examples = %q, want %q", got, NO_EXAMPLE)
+	}
+}
+
+func TestSummaryHints(t *testing.T) {
+	set(t, &HINTS, true)
+	set(t, &EXAMPLES, Examples{"animals": {"Bear"}})
+	var out bytes.Buffer
+	printSummary(&out, Round{Number: 1, Letters: []rune("B"), Prompts: []string{"Animals", "Bands"}}, []string{})
+	want := "    1.\tAnimals\n      \te.g. Bear\n    2.\tBands\n      \t" + msg("no-example") + "\n"
+	if !strings.HasSuffix(out.String(), want) {
+		t.Errorf("summary is\n%s\nwant it to end\n%s", out.String(), want)
+	}
+}
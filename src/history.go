@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// historyEntry is one line of the history log.
+type historyEntry struct {
+	Time    time.Time       `json:"time"`
+	Round   int             `json:"round"`
+	Letter  string          `json:"letter"`
+	Prompts []string        `json:"prompts"`
+	Scores  map[string]int  `json:"scores,omitempty"` // each player's points, if scored
+	Answers []historyAnswer `json:"answers,omitempty"`
+
+	LettersShuffled bool `json:"letters_shuffled,omitempty"` // the letter pool was reshuffled to draw the round
+	PromptsShuffled bool `json:"prompts_shuffled,omitempty"` // the prompt pool was
+
+	Title string `json:"title,omitempty"` // the game night's -title, if it had one
+	Date  string `json:"date,omitempty"`
+}
+
+// historyAnswer is one player's answer in a history log entry.
+type historyAnswer struct {
+	Player string `json:"player"`
+	Prompt int    `json:"prompt"` // index into the entry's prompts
+	Text   string `json:"text"`
+	Points int    `json:"points"`
+}
+
+// historyLog appends a JSON line per round to a file, keeping what's already
+// there.
+type historyLog struct {
+	file    *os.File
+	enc     *json.Encoder
+	session sessionInfo // recorded with every round
+}
+
+func openHistoryLog(path string, session sessionInfo) (*historyLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &historyLog{file: file, enc: json.NewEncoder(file), session: session}, nil
+}
+
+// WriteRound appends round, finished at the given time with scores (nil if it
+// wasn't scored), and syncs it to disk.
+func (h *historyLog) WriteRound(round Round, scores map[string]int, at time.Time) error {
+	entry := historyEntry{
+		Time:    at,
+		Round:   round.Number,
+		Letter:  string(round.Letters),
+		Prompts: round.Prompts,
+		Scores:  scores,
+
+		LettersShuffled: round.LettersShuffled,
+		PromptsShuffled: round.PromptsShuffled,
+
+		Title: h.session.Title,
+		Date:  h.session.Date,
+	}
+	for _, answer := range round.Answers {
+		entry.Answers = append(entry.Answers, historyAnswer{answer.Player, answer.Prompt, answer.Text, answer.Points})
+	}
+	if err := h.enc.Encode(entry); err != nil {
+		return err
+	}
+	return h.file.Sync()
+}
+
+func (h *historyLog) Close() error {
+	return h.file.Close()
+}
+
+// loadHistory reads every entry of the history log at path, for -practice.
+func loadHistory(path string) ([]historyEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return []historyEntry{}, err
+	}
+	defer file.Close()
+	entries := []historyEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry historyEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return []historyEntry{}, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		if len(entry.Prompts) == 0 {
+			return []historyEntry{}, fmt.Errorf("%s:%d: round has no prompts", path, n)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return []historyEntry{}, err
+	}
+	if len(entries) == 0 {
+		return entries, fmt.Errorf("%s has no rounds", path)
+	}
+	return entries, nil
+}
+
+// nextPractice starts the next round from Practice, looping back to the
+// first once they've all been played.
+func (g *Game) nextPractice() Round {
+	entry := g.Practice[g.practiced%len(g.Practice)]
+	g.practiced++
+	g.practicing = &entry
+	g.Played++
+	round := Round{Number: g.Played, Letters: []rune(entry.Letter), Prompts: entry.Prompts, Replay: true}
+	g.letters = round.Letters
+	g.last = &Round{Letters: round.Letters, Prompts: round.Prompts}
+	return round
+}
+
+// printPractice shows the answers recorded when entry was first played,
+// prompt by prompt, to compare with round's.
+func printPractice(w io.Writer, round Round, entry historyEntry) {
+	fmt.Fprintf(w, msg("last-time")+"\n", entry.Round, entry.Time.Local().Format("2 Jan 2006"))
+	if len(entry.Answers) == 0 {
+		fmt.Fprintln(w, "  "+msg("no-answers"))
+		return
+	}
+	for i, prompt := range entry.Prompts {
+		fmt.Fprintf(w, "  %d.\t%s\n", i+1, prompt)
+		for _, answer := range entry.Answers {
+			if answer.Prompt == i && strings.TrimSpace(answer.Text) != "" {
+				fmt.Fprintf(w, "      \t%s: %s (%d)\n", answer.Player, answer.Text, answer.Points)
+			}
+		}
+	}
+}
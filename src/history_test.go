@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// playLogged plays rounds unattended rounds, logging them to the history at
+// path.
+func playLogged(t *testing.T, path string, rounds int) {
+	t.Helper()
+	history, err := openHistoryLog(path, sessionInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("ABC"), Resolution: time.Second, Rounds: rounds}, TEST_PROMPTS, 1, nil, io.Discard)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.History = history
+	g.Run(&interrupter{out: io.Discard})
+}
+
+func TestHistoryLogLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	playLogged(t, path, 3)
+	playLogged(t, path, 2)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rounds := []int{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %d isn't JSON: %v\n%s", len(rounds)+1, err, scanner.Text())
+		}
+		if len(entry.Letter) != 1 || len(entry.Prompts) != 2 || entry.Time.IsZero() || entry.Scores != nil {
+			t.Errorf("line %d is %+v, want a letter, two prompts, a time and no scores", len(rounds)+1, entry)
+		}
+		rounds = append(rounds, entry.Round)
+	}
+	if want := []int{1, 2, 3, 1, 2}; !slices.Equal(rounds, want) {
+		t.Errorf("logged rounds %v, want %v: the second game appended to the first", rounds, want)
+	}
+
+	entries, err := loadHistory(path)
+	if err != nil || len(entries) != 5 {
+		t.Errorf("loadHistory read %d entries, %v; want 5", len(entries), err)
+	}
+}
+
+func TestHistoryLogScores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	history, err := openHistoryLog(path, sessionInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	round := roundWith("B", map[string][]string{"Al": {"Bear"}, "Bo": {"Bat"}})
+	scores := scoreRound(&round, ScoringRules{Unique: 1})
+	if err := history.WriteRound(round, scores, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	history.Close()
+	entries, err := loadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := entries[0]; got.Scores["Al"] != 1 || got.Scores["Bo"] != 1 || len(got.Answers) != 2 || got.Answers[0].Text != "Bear" {
+		t.Errorf("logged %+v, want both players' answers and a point each", got)
+	}
+}
+
+func TestLoadHistoryErrors(t *testing.T) {
+	for _, test := range []struct {
+		name, contents string
+	}{
+		{"empty", ""},
+		{"not JSON", `{"round": 1, "prompts": ["Animals"]}` + "\nround 2\n"},
+		{"no prompts", `{"round": 1}` + "\n"},
+	} {
+		if _, err := loadHistory(writeTestFile(t, "history.jsonl", test.contents)); err == nil {
+			t.Errorf("%s: loadHistory succeeded, want an error", test.name)
+		}
+	}
+}
+
+// SAMPLE_HISTORY is a history log of two rounds, one with answers.
+const SAMPLE_HISTORY = `{"time":"2024-03-01T20:00:00Z","round":1,"letter":"B","prompts":["Animals","Fruits"],"scores":{"Al":2},"answers":[{"player":"Al","prompt":0,"text":"Bear","points":1},{"player":"Al","prompt":1,"text":"Banana","points":1}]}
+
+{"time":"2024-03-01T20:05:00Z","round":2,"letter":"K","prompts":["Cities"]}
+`
+
+func TestNextPractice(t *testing.T) {
+	entries, err := loadHistory(writeTestFile(t, "history.jsonl", SAMPLE_HISTORY))
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("loadHistory read %d entries, %v; want 2, skipping the blank line", len(entries), err)
+	}
+	g := newTestGame(Config{NumPrompts: 3, Letters: []rune("ABC")}, TEST_PROMPTS)
+	g.Practice = entries
+	for i, want := range []struct {
+		letter  string
+		prompts []string
+	}{
+		{"B", []string{"Animals", "Fruits"}},
+		{"K", []string{"Cities"}},
+		{"B", []string{"Animals", "Fruits"}}, // looping back to the first
+	} {
+		round := g.NextRound()
+		if string(round.Letters) != want.letter || !slices.Equal(round.Prompts, want.prompts) || round.Number != i+1 || !round.Replay {
+			t.Errorf("practice round %d is %d %q %v, want %d %q %v, marked replay", i+1, round.Number, string(round.Letters), round.Prompts, i+1, want.letter, want.prompts)
+		}
+		if g.practicing == nil || g.practicing.Letter != want.letter {
+			t.Errorf("practice round %d is practicing %+v, want the %q round", i+1, g.practicing, want.letter)
+		}
+	}
+	if len(g.RemainingLetters()) != 3 || g.RemainingPrompts() != len(TEST_PROMPTS) {
+		t.Errorf("practicing drew from the pools: %d letters and %d prompts left", len(g.RemainingLetters()), g.RemainingPrompts())
+	}
+}
+
+func TestRunPractice(t *testing.T) {
+	entries, err := loadHistory(writeTestFile(t, "history.jsonl", SAMPLE_HISTORY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		loop   bool
+		played int
+	}{
+		{false, 2},
+		{true, 5},
+	} {
+		set(t, &PRACTICE_LOOP, test.loop)
+		var out bytes.Buffer
+		g := NewGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("ABC"), Resolution: time.Second, Rounds: 5}, TEST_PROMPTS, 1, nil, &out)
+		clock := newFakeClock()
+		clock.auto = true
+		g.Clock = clock
+		g.Practice = entries
+		g.Run(&interrupter{out: &out})
+		if g.Played != test.played {
+			t.Errorf("loop %v: played %d rounds of 5, want %d", test.loop, g.Played, test.played)
+		}
+		if n := strings.Count(out.String(), "Last time (round 1, "); n != (test.played+1)/2 {
+			t.Errorf("loop %v: compared with the first round %d times, want %d:\n%s", test.loop, n, (test.played+1)/2, out.String())
+		}
+		if !strings.Contains(out.String(), "Al: Bear (1)") || !strings.Contains(out.String(), msg("no-answers")) {
+			t.Errorf("loop %v: didn't show the recorded answers:\n%s", test.loop, out.String())
+		}
+	}
+}
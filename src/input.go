@@ -0,0 +1,20 @@
+package main
+
+import (
+	"bufio"
+	"io"
+)
+
+// readLines sends each line read from r on the returned channel, closing it
+// once r is exhausted.
+func readLines(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
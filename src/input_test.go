@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer safe to write from one goroutine while
+// another reads it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestReadLines(t *testing.T) {
+	got := []string{}
+	for line := range readLines(strings.NewReader("one\n\nthree")) {
+		got = append(got, line)
+	}
+	if strings.Join(got, "|") != "one||three" {
+		t.Errorf("read %q, want one, a blank line and three", got)
+	}
+}
+
+func TestPlayRoundAgainstBuffers(t *testing.T) {
+	set(t, &PLAYERS, []string{"Alice"})
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	lines := make(chan string)
+	var out lockedBuffer
+	g := NewGame(Config{Duration: time.Second, NumPrompts: 2, Letters: []rune("B"), Resolution: time.Second}, TEST_PROMPTS, 1, lines, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	done := make(chan struct{})
+	go func() {
+		g.Run(&interrupter{out: &out})
+		close(done)
+	}()
+
+	// Start a round, answer both prompts once it's over, then quit
+	lines <- ""
+	eventually(t, func() bool { return strings.Contains(out.String(), "Alice, enter your answers") })
+	lines <- "Bear"
+	lines <- "Apple"
+	close(lines)
+	<-done
+
+	if g.Played != 1 {
+		t.Errorf("played %d rounds, want 1", g.Played)
+	}
+	if g.Board.Totals["Alice"] != 1 {
+		t.Errorf("Alice scored %d, want 1 for Bear", g.Board.Totals["Alice"])
+	}
+	text := out.String()
+	for _, want := range []string{"Letter: B", "Time's up!", "Bear", "Apple", "doesn't start with B", "Standings after 1 rounds"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output is missing %q:\n%s", want, text)
+		}
+	}
+}
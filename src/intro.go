@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Intro prints the -verbose-intro screen for new players: how to play, then
+// the settings the game ended up with after every flag and config file, and
+// its seed.
+func (g *Game) Intro(w io.Writer) {
+	fmt.Fprintln(w, msg("rules"))
+	fmt.Fprintln(w, msg("settings"))
+	if SPRINT {
+		fmt.Fprintf(w, "  "+msg("intro-sprint")+"\n", formatClock(SPRINT_TIME))
+	} else {
+		fmt.Fprintf(w, "  "+msg("intro-length")+"\n", formatClock(g.Duration))
+	}
+	fmt.Fprintf(w, "  "+msg("intro-prompts")+"\n", g.promptCountText())
+	switch {
+	case g.NoLetter:
+		fmt.Fprintln(w, "  "+msg("intro-anything"))
+	case g.LettersPer > 1:
+		fmt.Fprintf(w, "  "+msg("intro-per")+"\n", string(g.Letters), g.LettersPer)
+	default:
+		fmt.Fprintf(w, "  "+msg("intro-letters")+"\n", string(g.Letters))
+	}
+	switch {
+	case g.Rounds > 0:
+		fmt.Fprintf(w, "  "+msg("intro-rounds")+"\n", g.Rounds)
+	case g.MaxRounds > 0:
+		fmt.Fprintf(w, "  "+msg("intro-max")+"\n", g.MaxRounds)
+	default:
+		fmt.Fprintln(w, "  "+msg("intro-endless"))
+	}
+	if len(PLAYERS) > 0 {
+		fmt.Fprintf(w, "  "+msg("intro-players")+"\n", strings.Join(PLAYERS, ", "))
+		fmt.Fprintf(w, "  "+msg("intro-scoring"), SCORER, SCORING.Unique, SCORING.Duplicate)
+		if SCORING.Alliteration > 0 {
+			fmt.Fprintf(w, msg("intro-allit"), SCORING.Alliteration)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "  "+msg("intro-dupes")+"\n", NORMALIZE)
+	}
+	fmt.Fprintf(w, "  "+msg("seed")+"\n", g.Seed)
+}
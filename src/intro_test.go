@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntro(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		config  Config
+		players []string
+		says    []string
+	}{
+		{"defaults", Config{Duration: 3 * time.Minute, NumPrompts: 12, Letters: LETTERS}, nil, []string{
+			"Round length: 3m0s", "Prompts per round: 12", "Letters: " + string(LETTERS), "Rounds: until you quit", "Seed: 1",
+		}},
+		{"overridden", Config{Duration: 90 * time.Second, NumPrompts: 5, MaxPrompts: 8, Letters: []rune("ABC"), LettersPer: 2, Rounds: 4}, []string{"Al", "Bo"}, []string{
+			"Round length: 1m30s", "Prompts per round: 5-8", "Letters: ABC, 2 per round", "Rounds: 4",
+			"Players: Al, Bo", "Scoring: standard, 1 a unique answer, 0 a duplicate",
+		}},
+		{"no letter", Config{Duration: time.Minute, NumPrompts: 3, Letters: LETTERS, NoLetter: true, MaxRounds: 6}, nil, []string{
+			"Letters: none, any answer counts", "Rounds: up to 6",
+		}},
+	} {
+		set(t, &PLAYERS, test.players)
+		var out bytes.Buffer
+		newTestGame(test.config, TEST_PROMPTS).Intro(&out)
+		if !strings.HasPrefix(out.String(), msg("rules")+"\n") {
+			t.Errorf("%s: doesn't start with the rules:\n%s", test.name, out.String())
+		}
+		for _, want := range test.says {
+			if !strings.Contains(out.String(), "  "+want) {
+				t.Errorf("%s: intro doesn't say %q:\n%s", test.name, want, out.String())
+			}
+		}
+		if mentionsPlayers := strings.Contains(out.String(), "Players:"); mentionsPlayers != (len(test.players) > 0) {
+			t.Errorf("%s: mentions players %v, want %v", test.name, mentionsPlayers, len(test.players) > 0)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MESSAGES is the catalog of interface text, keyed by -lang locale and then
+// by message. English is complete; other locales fall back to it for any
+// message they're missing.
+var MESSAGES = map[string]map[string]string{
+	"en": {
+		"welcome":        "Welcome to Scattergories!",
+		"rules":          "Each round draws a letter and a list of prompts. Before time runs out, think of an answer to every prompt that starts with the letter. Answers nobody else gave score best, so try to be original!",
+		"menu":           "Main menu:",
+		"menu-start":     "Start a round",
+		"menu-replay":    "Replay the last round",
+		"menu-settings":  "Change settings",
+		"menu-standings": "View standings",
+		"menu-quit":      "Quit",
+		"menu-choose":    "Choose a number, or press enter to start a round (%s shows the last one again). Press Ctrl+C to end a round early. ",
+		"prompts":        "Prompts:",
+		"stagger":        "The prompts will appear one by one over the next %s.",
+		"sprint":         "Category sprint! The prompts come one at a time, with %s on the clock for each.",
+		"sprint-keys":    "Press enter to pause or resume the timer, %s to add %s, %s to move on to the next prompt, %s to redraw, %s in the first %s to take the draw back, %s to pause the whole game or %s to quit.",
+		"sprint-prompt":  "Prompt %d of %d:",
+		"sprint-up":      "Time's up on that one!",
+		"reveal":         "Press enter to reveal the letter and start the clock. ",
+		"pick-letter":    "The letter drawn is %s. Type another letter to play instead, or press enter to keep it: ",
+		"pick-bad":       "%q isn't a single letter from A to Z.",
+		"controls":       "Press enter to pause or resume the timer, %s to add %s, %s to end the round, %s to redraw, %s in the first %s to take the draw back, %s to pause the whole game or %s to quit.",
+		"times-up":       "Time's up!",
+		"ended-early":    "Round ended early!",
+		"redrawing":      "Redrawing...",
+		"letters-reset":  "Every letter has come up, so they've all been shuffled back in.",
+		"prompts-reset":  "Every prompt has come up, so they've all been shuffled back in.",
+		"both-reset":     "Every letter and prompt has come up, so they've all been shuffled back in.",
+		"undone":         "Draw taken back; the next round will be the same.",
+		"undo-late":      "Too late to take the draw back; that's only allowed in the first %s.",
+		"bye":            "Bye!",
+		"paused":         "Game paused. Enter %s to carry on.",
+		"resumed":        "Game resumed.",
+		"rest":           "Next round in %s... ",
+		"sudden-death":   "Sudden death! Half the time, double the points.",
+		"start-timeout":  "Nobody chose, so here's the next round.",
+		"letter-format":  "Letter: %s",
+		"timer-format":   "Remaining time: %s",
+		"elapsed-format": "Elapsed time: %s",
+		"both-format":    "Elapsed %s / Remaining %s",
+		"time-left":      "%s left!",
+		"go":             "Go!",
+		"summary":        "Round %d summary",
+		"summary-letter": "Letter: %s",
+		"example":        "e.g. %s",
+		"no-example":     "(no example)",
+		"summary-unique": "Valid unique answers:",
+		"answers":        "%s, enter your answers%s (blank to skip):",
+		"answers-multi":  "%s, enter up to %d answers to each prompt%s, separated by commas or one per line, then a blank line:",
+		"invalid":        "(%sinvalid: %s)",
+		"not-in-dict":    "(%snot in dictionary)",
+		"too-many":       "(only %d answers count; dropped %s)",
+		"no-answer":      "no answer",
+		"bad-start":      "doesn't start with %s",
+		"unlisted":       "not in dictionary",
+		"for-letters":    "for %s",
+		"or":             "or",
+		"and":            "and",
+		"scores":         "Scores for round %d:",
+		"repeat":         "repeat",
+		"rejected":       "rejected",
+		"duplicate":      "duplicate",
+		"standings":      "Standings after %d rounds:",
+		"team-standings": "Team standings after %d rounds:",
+		"tie":            "%s are tied for first! One more prompt to settle it:",
+		"game-over":      "Game over!",
+		"stat-rounds":    "Rounds played: %d (%d ended early, %d redrawn)",
+		"stat-letters":   "Letters used: %s",
+		"stat-shuffles":  "Reshuffles: %d of the letters, %d of the prompts",
+		"stat-time":      "Time played: %s",
+		"no-replay":      "There's no round to replay yet.",
+		"no-scores":      "Nobody has scored yet.",
+		"no-recall":      "No rounds have been played yet, so there's nothing to show.",
+		"not-on-menu":    "%q isn't on the menu.",
+		"unknown-key":    "%q isn't a key; press enter to pause or resume the timer.",
+		"settings":       "Settings:",
+		"set-length":     "Round length (%s)",
+		"set-prompts":    "Prompts per round (%s)",
+		"set-letters":    "Letters (%s)",
+		"set-back":       "Back",
+		"set-choose":     "Choose a number: ",
+		"new-length":     "New round length, e.g. 2m or 90s: ",
+		"new-prompts":    "New number of prompts, up to %d, or a range like 8-14: ",
+		"new-letters":    "New letters, e.g. ABCDEFG: ",
+		"not-a-setting":  "%q isn't a setting",
+		"bad-length":     "%q isn't a positive length of time",
+		"intro-sprint":   "Round length: %s a prompt",
+		"intro-length":   "Round length: %s",
+		"intro-prompts":  "Prompts per round: %s",
+		"intro-anything": "Letters: none, any answer counts",
+		"intro-per":      "Letters: %s, %d per round",
+		"intro-letters":  "Letters: %s",
+		"intro-rounds":   "Rounds: %d",
+		"intro-max":      "Rounds: up to %d",
+		"intro-endless":  "Rounds: until you quit",
+		"intro-players":  "Players: %s",
+		"intro-scoring":  "Scoring: %s, %d a unique answer, %d a duplicate",
+		"intro-allit":    ", %d more for alliteration",
+		"intro-dupes":    "Duplicates ignore: %s",
+		"seed":           "Seed: %d",
+		"every-prompt":   "That's every prompt!",
+		"buzz-intro":     "Buzz in with your name or number and an answer, e.g. \"%s %s\". Enter %s to skip a prompt, %s to pause the whole game or %s to quit.",
+		"buzz-example":   "answer",
+		"buzz-nobody":    "Nobody got it in time.",
+		"skipped":        "Skipped.",
+		"buzz-who":       "Start with a player's name or number.",
+		"buzz-wrong":     "Not quite, %s: %s.",
+		"buzz-right":     "%s takes it with %s!",
+		"turns-intro":    "Take turns answering each prompt, with %s each on the clock. Enter an answer, or nothing to pass; %s ends the round, %s pauses the whole game and %s quits.",
+		"paused-label":   "PAUSED",
+		"all-out":        "Everyone's out of time!",
+		"out-of-time":    "%s is out of time!",
+		"sheet-paste":    "%s, paste your answers%s as \"1. answer\" lines, then a blank line:",
+		"sheets-up":      "Time's up for answer sheets!",
+		"no-number":      "line %d %q isn't numbered",
+		"no-prompt":      "line %d: there's no prompt %d",
+		"answered":       "line %d: prompt %d is already answered",
+		"sprint-answers": "Answers to %d. %s%s (blank to skip):",
+		"vote":           "Vote on each answer: enter y or n for each of %s, in order (nothing accepts it).",
+		"vote-rejected":  "Rejected.",
+		"vote-count":     "expected %d votes, got %d",
+		"vote-bad":       "votes are y or n, not %q",
+		"last-time":      "Last time (round %d, %s):",
+		"no-answers":     "No answers were recorded.",
+		"one-second":     "1 second",
+		"seconds":        "%d seconds",
+		"time-display":   `how to show times: "mm ss" (2m5s), "m:ss" (2:05) or "seconds" (125 seconds)`,
+		"round":          "Round %d",
+		"plan-round":     "Round %d: %s",
+		"preview":        "Host preview: round %d will be %s",
+		"resuming":       "Resuming after round %d",
+		"practicing":     "Practicing %d rounds from %s",
+		"serving":        "Serving the current round on %s",
+		"hosting":        "Hosting on %s",
+		"connected":      "Connected to %s, waiting for the next round...",
+		"round-over":     "Round over!",
+		"host-left":      "The host has left.",
+		"tui-title":      "Scattergories - round %d",
+		"tui-keys":       "[enter] pause/resume  [%s] add %s  [%s] end round  [%s] redraw  [%s] undo  [%s] pause all  [%s] quit",
+		"page-waiting":   "Waiting for the first round...",
+		"page-paused":    "%s (paused)",
+		"prompts-range":  "the number of prompts must be between 1 and %d",
+		"letters-short":  "each round draws %d letters, so at least that many are needed",
+	},
+	"es": {
+		"welcome":        "¡Bienvenidos a Scattergories!",
+		"rules":          "En cada ronda sale una letra y una lista de categorías. Antes de que se acabe el tiempo, piensa una respuesta para cada categoría que empiece por esa letra. Las respuestas que nadie más haya dado puntúan más, ¡así que sé original!",
+		"menu":           "Menú principal:",
+		"menu-start":     "Empezar una ronda",
+		"menu-replay":    "Repetir la última ronda",
+		"menu-settings":  "Cambiar la configuración",
+		"menu-standings": "Ver la clasificación",
+		"menu-quit":      "Salir",
+		"menu-choose":    "Elige un número, o pulsa enter para empezar una ronda (%s vuelve a mostrar la última). Pulsa Ctrl+C para terminar una ronda antes de tiempo. ",
+		"prompts":        "Categorías:",
+		"stagger":        "Las categorías irán apareciendo una a una durante %s.",
+		"sprint":         "¡Sprint de categorías! Las categorías salen de una en una, con %s de reloj para cada una.",
+		"sprint-keys":    "Pulsa enter para pausar o reanudar el reloj, %s para añadir %s, %s para pasar a la siguiente categoría, %s para sacar otra, %s en los primeros %s para deshacer el sorteo, %s para pausar todo el juego o %s para salir.",
+		"sprint-prompt":  "Categoría %d de %d:",
+		"sprint-up":      "¡Se acabó el tiempo para esta!",
+		"reveal":         "Pulsa enter para descubrir la letra y poner en marcha el reloj. ",
+		"pick-letter":    "Ha salido la letra %s. Escribe otra letra para jugar con ella, o pulsa enter para quedarte con esta: ",
+		"pick-bad":       "%q no es una sola letra de la A a la Z.",
+		"controls":       "Pulsa enter para pausar o reanudar el reloj, %s para añadir %s, %s para terminar la ronda, %s para sacar otra, %s en los primeros %s para deshacer el sorteo, %s para pausar todo el juego o %s para salir.",
+		"times-up":       "¡Se acabó el tiempo!",
+		"ended-early":    "¡Ronda terminada antes de tiempo!",
+		"redrawing":      "Sacando otra ronda...",
+		"letters-reset":  "Ya han salido todas las letras, así que se han vuelto a barajar.",
+		"prompts-reset":  "Ya han salido todas las categorías, así que se han vuelto a barajar.",
+		"both-reset":     "Ya han salido todas las letras y categorías, así que se han vuelto a barajar.",
+		"undone":         "Sorteo deshecho; la próxima ronda será la misma.",
+		"undo-late":      "Ya es tarde para deshacer el sorteo; solo se puede en los primeros %s.",
+		"bye":            "¡Adiós!",
+		"paused":         "Juego en pausa. Escribe %s para seguir.",
+		"resumed":        "Seguimos.",
+		"rest":           "Siguiente ronda en %s... ",
+		"sudden-death":   "¡Muerte súbita! La mitad de tiempo, el doble de puntos.",
+		"start-timeout":  "Nadie ha elegido, así que empieza la siguiente ronda.",
+		"letter-format":  "Letra: %s",
+		"timer-format":   "Tiempo restante: %s",
+		"elapsed-format": "Tiempo transcurrido: %s",
+		"both-format":    "Transcurrido %s / Restante %s",
+		"time-left":      "¡Quedan %s!",
+		"go":             "¡Ya!",
+		"summary":        "Resumen de la ronda %d",
+		"summary-letter": "Letra: %s",
+		"example":        "p. ej. %s",
+		"no-example":     "(sin ejemplo)",
+		"summary-unique": "Respuestas válidas y únicas:",
+		"answers":        "%s, escribe tus respuestas%s (en blanco para saltar):",
+		"answers-multi":  "%s, escribe hasta %d respuestas para cada categoría%s, separadas por comas o una por línea, y luego una línea en blanco:",
+		"invalid":        "(%sno vale: %s)",
+		"not-in-dict":    "(%sno está en el diccionario)",
+		"too-many":       "(solo cuentan %d respuestas; se descartan %s)",
+		"no-answer":      "sin respuesta",
+		"bad-start":      "no empieza por %s",
+		"unlisted":       "no está en el diccionario",
+		"for-letters":    "empezando por %s",
+		"or":             "o",
+		"and":            "y",
+		"scores":         "Puntos de la ronda %d:",
+		"repeat":         "repetida",
+		"rejected":       "rechazada",
+		"duplicate":      "duplicada",
+		"standings":      "Clasificación tras %d rondas:",
+		"team-standings": "Clasificación por equipos tras %d rondas:",
+		"tie":            "¡%s empatan en cabeza! Una categoría más para desempatar:",
+		"game-over":      "¡Fin del juego!",
+		"stat-rounds":    "Rondas jugadas: %d (%d terminadas antes de tiempo, %d vueltas a sacar)",
+		"stat-letters":   "Letras usadas: %s",
+		"stat-shuffles":  "Barajadas: %d de las letras, %d de las categorías",
+		"stat-time":      "Tiempo jugado: %s",
+		"no-replay":      "Todavía no hay ninguna ronda que repetir.",
+		"no-scores":      "Todavía no ha puntuado nadie.",
+		"no-recall":      "Todavía no se ha jugado ninguna ronda, así que no hay nada que mostrar.",
+		"not-on-menu":    "%q no está en el menú.",
+		"unknown-key":    "%q no es una tecla; pulsa enter para pausar o reanudar el reloj.",
+		"settings":       "Configuración:",
+		"set-length":     "Duración de la ronda (%s)",
+		"set-prompts":    "Categorías por ronda (%s)",
+		"set-letters":    "Letras (%s)",
+		"set-back":       "Volver",
+		"set-choose":     "Elige un número: ",
+		"new-length":     "Nueva duración de la ronda, p. ej. 2m o 90s: ",
+		"new-prompts":    "Nuevo número de categorías, hasta %d, o un intervalo como 8-14: ",
+		"new-letters":    "Nuevas letras, p. ej. ABCDEFG: ",
+		"not-a-setting":  "%q no es una opción",
+		"bad-length":     "%q no es una duración positiva",
+		"intro-sprint":   "Duración de la ronda: %s por categoría",
+		"intro-length":   "Duración de la ronda: %s",
+		"intro-prompts":  "Categorías por ronda: %s",
+		"intro-anything": "Letras: ninguna, vale cualquier respuesta",
+		"intro-per":      "Letras: %s, %d por ronda",
+		"intro-letters":  "Letras: %s",
+		"intro-rounds":   "Rondas: %d",
+		"intro-max":      "Rondas: hasta %d",
+		"intro-endless":  "Rondas: hasta que salgáis",
+		"intro-players":  "Jugadores: %s",
+		"intro-scoring":  "Puntuación: %s, %d por respuesta única, %d por duplicada",
+		"intro-allit":    ", %d más por aliteración",
+		"intro-dupes":    "Los duplicados ignoran: %s",
+		"seed":           "Semilla: %d",
+		"every-prompt":   "¡Esas son todas las categorías!",
+		"buzz-intro":     "Pulsa con tu nombre o número y una respuesta, p. ej. \"%s %s\". Escribe %s para saltar una categoría, %s para pausar todo el juego o %s para salir.",
+		"buzz-example":   "respuesta",
+		"buzz-nobody":    "Nadie la ha sacado a tiempo.",
+		"skipped":        "Saltada.",
+		"buzz-who":       "Empieza con el nombre o el número de un jugador.",
+		"buzz-wrong":     "Casi, %s: %s.",
+		"buzz-right":     "¡%s se la lleva con %s!",
+		"turns-intro":    "Responded por turnos a cada categoría, con %s de reloj para cada uno. Escribe una respuesta, o nada para pasar; %s termina la ronda, %s pausa todo el juego y %s sale.",
+		"paused-label":   "PAUSA",
+		"all-out":        "¡Se os ha acabado el tiempo a todos!",
+		"out-of-time":    "¡A %s se le ha acabado el tiempo!",
+		"sheet-paste":    "%s, pega tus respuestas%s como líneas \"1. respuesta\", y luego una línea en blanco:",
+		"sheets-up":      "¡Se acabó el tiempo para las hojas de respuestas!",
+		"no-number":      "la línea %d %q no lleva número",
+		"no-prompt":      "línea %d: no hay categoría %d",
+		"answered":       "línea %d: la categoría %d ya tiene respuesta",
+		"sprint-answers": "Respuestas a %d. %s%s (en blanco para saltar):",
+		"vote":           "Votad cada respuesta: escribe y (sí) o n (no) por cada uno de %s, en orden (nada la acepta).",
+		"vote-rejected":  "Rechazada.",
+		"vote-count":     "se esperaban %d votos, hay %d",
+		"vote-bad":       "los votos son y o n, no %q",
+		"last-time":      "La última vez (ronda %d, %s):",
+		"no-answers":     "No se guardó ninguna respuesta.",
+		"one-second":     "1 segundo",
+		"seconds":        "%d segundos",
+		"time-display":   `cómo mostrar los tiempos: "mm ss" (2m5s), "m:ss" (2:05) o "seconds" (125 segundos)`,
+		"round":          "Ronda %d",
+		"plan-round":     "Ronda %d: %s",
+		"preview":        "Vista previa del anfitrión: la ronda %d será con %s",
+		"resuming":       "Seguimos después de la ronda %d",
+		"practicing":     "Practicando %d rondas de %s",
+		"serving":        "Sirviendo la ronda actual en %s",
+		"hosting":        "Anfitrión en %s",
+		"connected":      "Conectado a %s, esperando a la siguiente ronda...",
+		"round-over":     "¡Se acabó la ronda!",
+		"host-left":      "El anfitrión se ha ido.",
+		"tui-title":      "Scattergories - ronda %d",
+		"tui-keys":       "[enter] pausar/reanudar  [%s] añadir %s  [%s] terminar ronda  [%s] sacar otra  [%s] deshacer  [%s] pausar todo  [%s] salir",
+		"page-waiting":   "Esperando a la primera ronda...",
+		"page-paused":    "%s (en pausa)",
+		"prompts-range":  "el número de categorías debe estar entre 1 y %d",
+		"letters-short":  "cada ronda saca %d letras, así que hacen falta al menos esas",
+	},
+}
+
+// msg returns the LANGUAGE text for key, or the English text if it has none.
+func msg(key string) string {
+	if text, ok := MESSAGES[LANGUAGE][key]; ok {
+		return text
+	}
+	slog.Debug("missing translation, using English", "lang", LANGUAGE, "key", key)
+	return MESSAGES["en"][key]
+}
+
+// checkLanguage reports an error if lang isn't in MESSAGES.
+func checkLanguage(lang string) error {
+	if _, ok := MESSAGES[lang]; !ok {
+		locales := []string{}
+		for locale := range MESSAGES {
+			locales = append(locales, locale)
+		}
+		sort.Strings(locales)
+		return fmt.Errorf("no messages for %q; have %s", lang, strings.Join(locales, ", "))
+	}
+	return nil
+}
+
+// localizeFormats sets the -letter-format, -timer-format, -elapsed-format and
+// -both-format flags in fs to their LANGUAGE templates, leaving any flag
+// already set alone.
+func localizeFormats(fs *flag.FlagSet) error {
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	for _, name := range []string{"letter-format", "timer-format", "elapsed-format", "both-format"} {
+		if explicit[name] {
+			continue
+		}
+		if err := fs.Set(name, msg(name)); err != nil {
+			return fmt.Errorf("setting %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// localizedPath returns the LANGUAGE version of a prompts file, named with
+// the locale before the extension (scattergories.es.txt for
+// scattergories.txt), if there is one, and otherwise path itself.
+func localizedPath(path string) string {
+	if LANGUAGE == "en" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	localized := strings.TrimSuffix(path, ext) + "." + LANGUAGE + ext
+	if _, err := os.Stat(localized); err == nil {
+		return localized
+	} else if !errors.Is(err, os.ErrNotExist) {
+		slog.Warn("can't open localized prompts file", "path", localized, "err", err)
+	}
+	slog.Debug("no localized prompts file, using the default", "path", path, "lang", LANGUAGE)
+	return path
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestMsgSwitchesLanguage(t *testing.T) {
+	set(t, &LANGUAGE, "en")
+	if got := msg("times-up"); got != "Time's up!" {
+		t.Errorf("English times-up = %q", got)
+	}
+	LANGUAGE = "es"
+	if got := msg("times-up"); got != "¡Se acabó el tiempo!" {
+		t.Errorf("Spanish times-up = %q", got)
+	}
+	LANGUAGE = "xx"
+	if got := msg("times-up"); got != "Time's up!" {
+		t.Errorf("times-up with no translation = %q, want the English", got)
+	}
+}
+
+func TestSummaryInSpanish(t *testing.T) {
+	set(t, &HINTS, false)
+	round := Round{Number: 2, Letters: []rune("AB"), Prompts: []string{"Animales"}}
+	render := func(lang string) string {
+		set(t, &LANGUAGE, lang)
+		var out bytes.Buffer
+		printSummary(&out, round, []string{})
+		return out.String()
+	}
+	if en, es := render("en"), render("es"); en != "Round 2 summary\n  Letter: A or B\n  Prompts:\n    1.\tAnimales\n" ||
+		!strings.HasPrefix(es, "Resumen de la ronda 2\n") || strings.Contains(es, "Round") || strings.Contains(es, " or ") {
+		t.Errorf("English summary\n%s\nSpanish summary\n%s", en, es)
+	}
+}
+
+func TestTranslationsMatchEnglish(t *testing.T) {
+	verbs := func(s string) string {
+		kinds := []string{}
+		for i := 0; i < len(s)-1; i++ {
+			if s[i] == '%' {
+				kinds = append(kinds, s[i+1:i+2])
+				i++
+			}
+		}
+		return strings.Join(kinds, "")
+	}
+	for lang, messages := range MESSAGES {
+		for key, english := range MESSAGES["en"] {
+			text, ok := messages[key]
+			if !ok {
+				t.Errorf("%s has no %q", lang, key)
+			} else if verbs(text) != verbs(english) {
+				t.Errorf("%s %q formats %q, but English formats %q", lang, key, verbs(text), verbs(english))
+			}
+		}
+		for key := range messages {
+			if _, ok := MESSAGES["en"][key]; !ok {
+				t.Errorf("%s has %q, which English doesn't", lang, key)
+			}
+		}
+	}
+}
+
+func TestCheckLanguage(t *testing.T) {
+	for _, lang := range []string{"en", "es"} {
+		if err := checkLanguage(lang); err != nil {
+			t.Errorf("checkLanguage(%q) = %v", lang, err)
+		}
+	}
+	if err := checkLanguage("fr"); err == nil || !strings.Contains(err.Error(), "en, es") {
+		t.Errorf("checkLanguage(fr) = %v, want the locales there are", err)
+	}
+}
+
+func TestLocalizeFormats(t *testing.T) {
+	set(t, &LANGUAGE, "es")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	letter := fs.String("letter-format", "Letter: %s", "")
+	timer := fs.String("timer-format", "Remaining time: %s", "")
+	fs.String("elapsed-format", "", "")
+	fs.String("both-format", "", "")
+	if err := fs.Parse([]string{"-timer-format", "%s!"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := localizeFormats(fs); err != nil {
+		t.Fatal(err)
+	}
+	if *letter != "Letra: %s" || *timer != "%s!" {
+		t.Errorf("formats %q and %q, want the Spanish letter format and the timer format given", *letter, *timer)
+	}
+}
+
+func TestLocalizedPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prompts.txt")
+	spanish := filepath.Join(dir, "prompts.es.txt")
+	if err := os.WriteFile(spanish, []byte("Animales\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		lang, want string
+	}{
+		{"en", path},
+		{"es", spanish},
+		{"xx", path},
+	} {
+		set(t, &LANGUAGE, test.lang)
+		if got := localizedPath(path); got != test.want {
+			t.Errorf("%s: localizedPath = %q, want %q", test.lang, got, test.want)
+		}
+	}
+}
+
+func TestSpanishGame(t *testing.T) {
+	out, code := runGame(t, "", "-lang", "es", "-rounds", "1", "-no-wait", "-seed", "7")
+	if code != 0 {
+		t.Fatalf("exited %d:\n%s", code, out)
+	}
+	for _, want := range []string{"Letra: ", "¡Se acabó el tiempo!"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Spanish game is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Time's up!") {
+		t.Errorf("Spanish game has English messages:\n%s", out)
+	}
+}
+
+// englishIn lists the catalog's English text, or the parts of it between
+// values, that out has and the Spanish doesn't. Lone lowercase words are
+// left out, as they turn up in names like remaining_seconds.
+func englishIn(out string) []string {
+	verb := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	found := []string{}
+	for key, text := range MESSAGES["en"] {
+		for _, part := range verb.Split(text, -1) {
+			part = strings.TrimFunc(part, func(r rune) bool { return !unicode.IsLetter(r) })
+			words := strings.Contains(part, " ") || unicode.IsUpper([]rune(part + " ")[0])
+			if len(part) >= 5 && words && !strings.Contains(MESSAGES["es"][key], part) && strings.Contains(out, part) {
+				found = append(found, fmt.Sprintf("%s %q", key, part))
+			}
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+func TestSpanishLeavesNoEnglish(t *testing.T) {
+	dir := t.TempDir()
+	prompts := writeTestFile(t, "prompts.txt", "Animales\nCiudades\nComidas\nDeportes\n")
+	session, history := filepath.Join(dir, "session.json"), filepath.Join(dir, "history.jsonl")
+	base := []string{"-lang", "es", "-prompts-file", prompts, "-prompts", "2", "-no-wait", "-seed", "7"}
+	var all strings.Builder
+	for _, args := range [][]string{
+		{"-rounds", "2", "-session", session, "-history", history, "-serve", "127.0.0.1:0", "-host", "127.0.0.1:0", "-host-preview"},
+		{"-rounds", "3", "-resume", session},
+		{"-practice", history},
+		{"-plan", "2"},
+	} {
+		out, code := runGame(t, "", append(base, args...)...)
+		if code != 0 {
+			t.Fatalf("%q exited %d:\n%s", args, code, out)
+		}
+		all.WriteString(out)
+	}
+
+	set(t, &LANGUAGE, "es")
+	set(t, &LETTER_FORMAT, msg("letter-format"))
+	set(t, &TIMER_FORMAT, msg("timer-format"))
+	// Following a host, on a line and on the whole screen
+	for _, tui := range []bool{false, true} {
+		set(t, &TUI, tui)
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			state := roundState{Active: true, Round: 2, Letter: "B", Prompts: []string{"Animales"}, Remaining: 30, Total: 60}
+			encoder := json.NewEncoder(conn)
+			for _, kind := range []string{"round", "tick", "end"} {
+				encoder.Encode(netMessage{kind, state})
+			}
+		}()
+		if err := joinGame(ln.Addr().String(), &all); err != nil {
+			t.Fatal(err)
+		}
+		ln.Close()
+	}
+
+	// The web page
+	g := newTestGame(Config{NumPrompts: 2, Letters: []rune("B"), LettersPer: 2}, TEST_PROMPTS)
+	w := serveTest(t, newServer(":0", g, &interrupter{}).Handler, "GET", "/", "")
+	all.WriteString(strings.ReplaceAll(w.Body.String(), `\u0027`, "'"))
+
+	// An answers file
+	round := Round{Number: 3, Letters: []rune("B"), Prompts: []string{"Animales"}, Answers: []Answer{{Player: "Ana", Text: "Burro", Points: 1}}}
+	path, err := writeAnswersFile(dir, round, []string{"Ana"}, sessionInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all.Write(data)
+
+	// Settings the menu turns down
+	fmt.Fprintln(&all, g.setNumPrompts("99"), g.setLetters("A"))
+
+	out := all.String()
+	if left := englishIn(out); len(left) > 0 {
+		t.Errorf("Spanish output has English: %s\n%s", strings.Join(left, ", "), out)
+	}
+	for _, want := range []string{"Seguimos después de la ronda 2", "Practicando 2 rondas", "Sirviendo la ronda actual", "Anfitrión en", "Vista previa del anfitrión",
+		"Ronda 1: ", "Conectado a", "¡Se acabó la ronda!", "El anfitrión se ha ido.", "Scattergories - ronda 2", "[enter] pausar/reanudar",
+		"Esperando a la primera ronda", "(en pausa)", "Ronda 3 empezando por B", "entre 1 y", "cada ronda saca 2 letras"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Spanish output is missing %q:\n%s", want, out)
+		}
+	}
+}
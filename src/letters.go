@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LetterSource provides the letters a game draws from, and how heavily each
+// is weighted (nil or empty for evenly).
+type LetterSource interface {
+	Letters() ([]rune, map[rune]float64, error)
+}
+
+// letterSpec is a LetterSource written the way the flags give one: a set of
+// letters, letters to leave out of it, and LETTER=WEIGHT pairs.
+type letterSpec struct {
+	set, exclude, weights string
+}
+
+func (s letterSpec) Letters() ([]rune, map[rune]float64, error) {
+	letters, err := buildLetters(s.set, s.exclude)
+	if err != nil {
+		return []rune{}, nil, fmt.Errorf("-letters: %w", err)
+	}
+	weights, err := parseWeights(s.weights)
+	if err != nil {
+		return []rune{}, nil, fmt.Errorf("-letter-weights: %w", err)
+	}
+	return letters, weights, nil
+}
+
+// LETTER_PRESETS are the letter sets -letter-preset can pick by name.
+var LETTER_PRESETS = map[string]letterSpec{
+	"standard":        {set: "ABCDEFGHIJKLMNOPRSTW"},
+	"all":             {set: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+	"vowel-heavy":     {set: "ABCDEFGHIJKLMNOPRSTUW", weights: "A=3,E=3,I=3,O=3,U=3"},
+	"consonants-only": {set: "BCDFGHJKLMNPQRSTVWXYZ"},
+}
+
+// buildLetters returns the letters in set minus those in exclude, uppercased
+// and without repeats. Anything outside A-Z is rejected, as is an empty
+// result.
+func buildLetters(set, exclude string) ([]rune, error) {
+	excluded := map[rune]bool{}
+	for _, r := range strings.ToUpper(exclude) {
+		if r < 'A' || r > 'Z' {
+			return []rune{}, fmt.Errorf("can't exclude %q: only A-Z are allowed", r)
+		}
+		excluded[r] = true
+	}
+	letters := []rune{}
+	seen := map[rune]bool{}
+	for _, r := range strings.ToUpper(set) {
+		if r < 'A' || r > 'Z' {
+			return []rune{}, fmt.Errorf("%q isn't a letter from A-Z", r)
+		}
+		if excluded[r] || seen[r] {
+			continue
+		}
+		seen[r] = true
+		letters = append(letters, r)
+	}
+	if len(letters) == 0 {
+		return letters, fmt.Errorf("no letters left to play with")
+	}
+	return letters, nil
+}
+
+// parseWeights parses comma-separated LETTER=WEIGHT pairs, e.g. "S=3,K=0.5,Z=0".
+func parseWeights(s string) (map[rune]float64, error) {
+	weights := map[rune]float64{}
+	for _, item := range splitList(s) {
+		letter, weight, ok := strings.Cut(item, "=")
+		letter = strings.ToUpper(strings.TrimSpace(letter))
+		if !ok || len(letter) != 1 || letter[0] < 'A' || letter[0] > 'Z' {
+			return map[rune]float64{}, fmt.Errorf("%q should look like S=3", item)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if err != nil || w < 0 {
+			return map[rune]float64{}, fmt.Errorf("%q needs a non-negative weight", item)
+		}
+		weights[rune(letter[0])] = w
+	}
+	return weights, nil
+}
+
+// letterWeight is letter's weight, defaulting to 1 for unlisted letters.
+func letterWeight(weights map[rune]float64, letter rune) float64 {
+	if w, ok := weights[letter]; ok {
+		return w
+	}
+	return 1
+}
+
+// weightedLetters picks n different letters, each with probability
+// proportional to its weight among those left. It avoids the letters in prev
+// unless there aren't enough others with any weight, and never picks letters
+// weighing zero, so it returns fewer than n if too few have weight.
+func weightedLetters(rng *rand.Rand, letters []rune, weights map[rune]float64, prev []rune, n int) []rune {
+	picked := []rune{}
+	for len(picked) < n {
+		letter, ok := weightedLetter(rng, letters, weights, append(slices.Clone(prev), picked...))
+		if !ok {
+			if letter, ok = weightedLetter(rng, letters, weights, picked); !ok {
+				break
+			}
+		}
+		picked = append(picked, letter)
+	}
+	return picked
+}
+
+// weightedLetter picks one of letters, other than those in avoid, with
+// probability proportional to its weight. It reports false if none of them
+// has any weight.
+func weightedLetter(rng *rand.Rand, letters []rune, weights map[rune]float64, avoid []rune) (rune, bool) {
+	total := 0.0
+	candidates := []rune{}
+	for _, letter := range letters {
+		if w := letterWeight(weights, letter); w > 0 && !slices.Contains(avoid, letter) {
+			total += w
+			candidates = append(candidates, letter)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, false
+	}
+	pick := rng.Float64() * total
+	for _, letter := range candidates {
+		if pick -= letterWeight(weights, letter); pick < 0 {
+			return letter, true
+		}
+	}
+	return candidates[len(candidates)-1], true
+}
+
+// printLetters writes the round's LETTER_FORMAT line, or nothing if the round
+// has no letters.
+func printLetters(w io.Writer, letters []rune) {
+	if len(letters) > 0 {
+		fmt.Fprintf(w, LETTER_FORMAT+"\n", painted{COLOR_LETTER, letterText(letters)})
+	}
+}
+
+// forLetters is " for " and the letters, for sentences like "enter your
+// answers for A", or "" if there are none.
+func forLetters(letters []rune) string {
+	if len(letters) == 0 {
+		return ""
+	}
+	return " " + fmt.Sprintf(msg("for-letters"), letterText(letters))
+}
+
+// letterText writes a round's letters for players, e.g. "A" or "A or B".
+func letterText(letters []rune) string {
+	names := []string{}
+	for _, letter := range letters {
+		names = append(names, string(letter))
+	}
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " " + msg("or") + " " + names[len(names)-1]
+}
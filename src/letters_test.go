@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildLetters(t *testing.T) {
+	for _, test := range []struct {
+		set, exclude, want string
+	}{
+		{"ABCDEFGHIJKLMNOPRSTW", "", "ABCDEFGHIJKLMNOPRSTW"},
+		{"abc", "", "ABC"},
+		{"ABCA", "", "ABC"},
+		{"ABCDEFGHIJKLMNOPRSTW", "KJ", "ABCDEFGHILMNOPRSTW"},
+		{"ABCDE", "ea", "BCD"},
+		{"ABC", "XYZ", "ABC"},
+	} {
+		letters, err := buildLetters(test.set, test.exclude)
+		if err != nil || string(letters) != test.want {
+			t.Errorf("buildLetters(%q, %q) = %q, %v; want %q", test.set, test.exclude, string(letters), err, test.want)
+		}
+	}
+}
+
+func TestBuildLettersErrors(t *testing.T) {
+	for _, test := range []struct {
+		set, exclude string
+	}{
+		{"", ""},
+		{"ABC", "ABC"},
+		{"AB1", ""},
+		{"AB C", ""},
+		{"ABÉ", ""},
+		{"ABC", "A-"},
+	} {
+		if letters, err := buildLetters(test.set, test.exclude); err == nil {
+			t.Errorf("buildLetters(%q, %q) = %q, want an error", test.set, test.exclude, string(letters))
+		}
+	}
+}
+
+func TestLetterSpecWrapsErrors(t *testing.T) {
+	if _, _, err := (letterSpec{set: "ABC", exclude: "ABC"}).Letters(); err == nil || !strings.HasPrefix(err.Error(), "-letters:") {
+		t.Errorf("excluding every letter gave %v, want a -letters error", err)
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := parseWeights("S=3, k=0.5,Z=0")
+	if err != nil || len(weights) != 3 || weights['S'] != 3 || weights['K'] != 0.5 || weights['Z'] != 0 {
+		t.Errorf("parseWeights = %v, %v; want S=3, K=0.5 and Z=0", weights, err)
+	}
+	for _, bad := range []string{"S", "S=", "S=-1", "SS=2", "1=2", "S=lots"} {
+		if _, err := parseWeights(bad); err == nil {
+			t.Errorf("parseWeights(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestWeightedLettersDistribution(t *testing.T) {
+	const ROUNDS = 6000
+	weights := map[rune]float64{'S': 4, 'K': 0.5, 'Z': 0}
+	draws := func() map[rune]int {
+		g := newTestGame(Config{NumPrompts: 1, Letters: []rune("SKZAB"), LetterWeights: weights}, TEST_PROMPTS)
+		counts := map[rune]int{}
+		var last rune
+		for i := 0; i < ROUNDS; i++ {
+			round := g.NextRound()
+			if len(round.Letters) != 1 {
+				t.Fatalf("round %d drew %q, want one letter", round.Number, string(round.Letters))
+			}
+			letter := round.Letters[0]
+			if letter == last {
+				t.Fatalf("round %d drew %c twice running", round.Number, letter)
+			}
+			last = letter
+			counts[letter]++
+		}
+		return counts
+	}
+	counts := draws()
+	if counts['Z'] != 0 {
+		t.Errorf("Z weighs nothing but was drawn %d times", counts['Z'])
+	}
+	// Avoiding repeats flattens the weights, but S should still come up well
+	// ahead of the evenly weighted A and B, and K well behind them.
+	if !(counts['S'] > counts['A']*3/2 && counts['S'] > counts['B']*3/2) {
+		t.Errorf("S was drawn %d times against A's %d and B's %d, want it clearly ahead", counts['S'], counts['A'], counts['B'])
+	}
+	if !(counts['K']*3/2 < counts['A'] && counts['K']*3/2 < counts['B']) {
+		t.Errorf("K was drawn %d times against A's %d and B's %d, want it clearly behind", counts['K'], counts['A'], counts['B'])
+	}
+	if again := draws(); !maps.Equal(again, counts) {
+		t.Errorf("same seed drew %v, then %v", counts, again)
+	}
+}
+
+func TestWeightedLettersFallsBackToRepeats(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	weights := map[rune]float64{'B': 0, 'C': 0}
+	for i := 0; i < 10; i++ {
+		if picked := weightedLetters(rng, []rune("ABC"), weights, []rune("A"), 1); string(picked) != "A" {
+			t.Fatalf("picked %q, want A again as the only letter with any weight", string(picked))
+		}
+	}
+	if picked := weightedLetters(rng, []rune("ABC"), weights, nil, 2); string(picked) != "A" {
+		t.Errorf("picked %q for two letters, want just A", string(picked))
+	}
+}
+
+func TestMultiLetterDraws(t *testing.T) {
+	for _, config := range []Config{
+		{NumPrompts: 1, Letters: []rune("ABCDE"), LettersPer: 2},
+		{NumPrompts: 1, Letters: []rune("ABC"), LettersPer: 3},
+		{NumPrompts: 1, Letters: []rune("ABCDE"), LettersPer: 2, RepeatLetters: true},
+		{NumPrompts: 1, Letters: []rune("ABCDE"), LettersPer: 2, LetterWeights: map[rune]float64{'A': 5}},
+	} {
+		g := newTestGame(config, TEST_PROMPTS)
+		for i := 0; i < 50; i++ {
+			round := g.NextRound()
+			if len(round.Letters) != config.LettersPer {
+				t.Fatalf("%+v: round %d drew %q", config, round.Number, string(round.Letters))
+			}
+			for j, letter := range round.Letters {
+				if !slices.Contains(config.Letters, letter) || slices.Contains(round.Letters[:j], letter) {
+					t.Fatalf("%+v: round %d drew %q", config, round.Number, string(round.Letters))
+				}
+			}
+		}
+	}
+}
+
+func TestLetterText(t *testing.T) {
+	for _, test := range []struct {
+		letters, want string
+	}{
+		{"", ""},
+		{"A", "A"},
+		{"AB", "A or B"},
+		{"ABC", "A, B or C"},
+	} {
+		if got := letterText([]rune(test.letters)); got != test.want {
+			t.Errorf("letterText(%q) = %q, want %q", test.letters, got, test.want)
+		}
+	}
+	var out bytes.Buffer
+	printLetters(&out, []rune("AB"))
+	printLetters(&out, []rune{})
+	if out.String() != "Letter: A or B\n" {
+		t.Errorf("printLetters wrote %q", out.String())
+	}
+}
+
+func TestTooManyLettersPerRound(t *testing.T) {
+	out, code := runGame(t, "", "-letters", "AB", "-letters-per-round", "3", "-rounds", "1", "-no-wait")
+	if code == 0 || !strings.Contains(out, "-letters-per-round") {
+		t.Errorf("drawing 3 of 2 letters exited %d:\n%s", code, out)
+	}
+}
+
+func TestLetterPresets(t *testing.T) {
+	vowels := "AEIOU"
+	for _, test := range []struct {
+		name, letters string
+		weights       map[rune]float64
+	}{
+		{"standard", string(LETTERS), map[rune]float64{}},
+		{"all", "ABCDEFGHIJKLMNOPQRSTUVWXYZ", map[rune]float64{}},
+		{"vowel-heavy", "ABCDEFGHIJKLMNOPRSTUW", map[rune]float64{'A': 3, 'E': 3, 'I': 3, 'O': 3, 'U': 3}},
+		{"consonants-only", "BCDFGHJKLMNPQRSTVWXYZ", map[rune]float64{}},
+	} {
+		spec, ok := LETTER_PRESETS[test.name]
+		if !ok {
+			t.Errorf("no %s preset", test.name)
+			continue
+		}
+		letters, weights, err := spec.Letters()
+		if err != nil || string(letters) != test.letters || !maps.Equal(weights, test.weights) {
+			t.Errorf("%s: got %q weighted %v, %v; want %q weighted %v", test.name, string(letters), weights, err, test.letters, test.weights)
+		}
+		for _, letter := range letters {
+			if letter < 'A' || letter > 'Z' {
+				t.Errorf("%s: %q isn't from A-Z", test.name, letter)
+			}
+		}
+		if test.name == "consonants-only" && strings.ContainsAny(string(letters), vowels) {
+			t.Errorf("%s: has vowels: %q", test.name, string(letters))
+		}
+	}
+	if len(LETTER_PRESETS) != 4 {
+		t.Errorf("%d presets, want a test for each", len(LETTER_PRESETS))
+	}
+}
+
+func TestLetterPresetOverrides(t *testing.T) {
+	// plannedLetters plans rounds with args and returns every letter drawn
+	plannedLetters := func(args ...string) string {
+		out, code := runGame(t, "", append([]string{"-plan", "30", "-prompts", "1", "-repeat-letters"}, args...)...)
+		if code != 0 {
+			t.Fatalf("%q exited %d:\n%s", args, code, out)
+		}
+		drawn := ""
+		for _, line := range strings.Split(out, "\n") {
+			if _, letter, ok := strings.Cut(line, ": "); ok && strings.HasPrefix(line, "Round ") {
+				drawn += letter
+			}
+		}
+		return drawn
+	}
+	config := writeTestFile(t, "game.json", `{"letters": "AEIOU"}`)
+	for _, args := range [][]string{
+		{"-difficulty", "easy", "-letter-preset", "consonants-only"},
+		{"-difficulty", "hard", "-letter-preset", "consonants-only"},
+		{"-config", config, "-letter-preset", "consonants-only"},
+	} {
+		if drawn := plannedLetters(args...); len(drawn) != 30 || strings.ContainsAny(drawn, "AEIOU") {
+			t.Errorf("%q drew %q, want 30 consonants", args, drawn)
+		}
+	}
+	if drawn := plannedLetters("-difficulty", "easy", "-letter-preset", "consonants-only", "-letters", "ABE"); len(drawn) != 30 || strings.Trim(drawn, "ABE") != "" {
+		t.Errorf("-letters on the command line drew %q, want only A, B and E", drawn)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// lintProblem is a mistake found in a prompts file.
+type lintProblem struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Problem string `json:"problem"`
+}
+
+func (p lintProblem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.Path, p.Line, p.Problem)
+}
+
+// lintPromptsFile checks the prompts file at path. Unlike readPromptsFile, it
+// doesn't fall back to the built-in prompts if the file can't be opened.
+func lintPromptsFile(path string) ([]lintProblem, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return []lintProblem{}, err
+	}
+	defer file.Close()
+	return lintPrompts(path, file)
+}
+
+// lintPrompts reads a prompts file from r, named path in the problems it
+// reports, and returns what's wrong with it line by line: blank lines, lines
+// with no prompt text, tags that look like weights but aren't valid ones,
+// more than one weight, and prompts repeated from an earlier line (ignoring
+// case if DEDUP_IGNORE_CASE is set).
+func lintPrompts(path string, r io.Reader) ([]lintProblem, error) {
+	problems := []lintProblem{}
+	report := func(line int, format string, args ...any) {
+		problems = append(problems, lintProblem{path, line, fmt.Sprintf(format, args...)})
+	}
+	seen := map[string]int{} // line each prompt first appeared on
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			report(n, "blank line")
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		text, tags, _ := strings.Cut(line, "|")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			report(n, "no prompt text")
+			continue
+		}
+		weights := 0
+		for _, tag := range splitList(tags) {
+			if _, isWeight, err := parseWeight(tag); err != nil {
+				report(n, "%v", err)
+			} else if isWeight {
+				weights++
+			}
+		}
+		if weights > 1 {
+			report(n, "%d weights given; only the last one counts", weights)
+		}
+		key := text
+		if DEDUP_IGNORE_CASE {
+			key = strings.ToLower(key)
+		}
+		if first, ok := seen[key]; ok {
+			report(n, "duplicate of line %d: %q", first, text)
+		} else {
+			seen[key] = n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return []lintProblem{}, err
+	}
+	return problems, nil
+}
+
+// looksLikeWeight reports whether tag was probably meant as a weight: it
+// starts like a number does.
+func looksLikeWeight(tag string) bool {
+	return strings.IndexAny(tag[:1], "0123456789.+-") == 0
+}
+
+// printLint writes problems to w, one per line, or as a JSON array if asJSON
+// is set.
+func printLint(w io.Writer, problems []lintProblem, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(problems)
+	}
+	for _, problem := range problems {
+		fmt.Fprintln(w, problem)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// DEFECTIVE_PROMPTS has one of each mistake lintPrompts catches.
+const DEFECTIVE_PROMPTS = `# a pack with mistakes
+Animals|5
+
+|food
+Bands|lots,1x
+Cars|1,2
+animals
+Animals|music
+Desserts|-3
+Elements|kids
+`
+
+func TestLintPrompts(t *testing.T) {
+	set(t, &DEDUP_IGNORE_CASE, false)
+	problems, err := lintPrompts("pack.txt", strings.NewReader(DEFECTIVE_PROMPTS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"pack.txt:3: blank line",
+		"pack.txt:4: no prompt text",
+		`pack.txt:5: bad weight "1x": weights are finite numbers above 0`,
+		"pack.txt:6: 2 weights given; only the last one counts",
+		`pack.txt:8: duplicate of line 2: "Animals"`,
+		`pack.txt:9: bad weight "-3": weights are finite numbers above 0`,
+	}
+	got := []string{}
+	for _, problem := range problems {
+		got = append(got, problem.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	DEDUP_IGNORE_CASE = true
+	problems, _ = lintPrompts("pack.txt", strings.NewReader("Animals\nanimals\n"))
+	if len(problems) != 1 || problems[0].Line != 2 {
+		t.Errorf("ignoring case, problems are %v, want line 2 a duplicate", problems)
+	}
+}
+
+func TestLintCleanFile(t *testing.T) {
+	problems, err := lintPromptsFile(writeTestFile(t, "prompts.txt", "# fine\nAnimals|kids,2\nBands\n"))
+	if err != nil || len(problems) != 0 {
+		t.Errorf("lintPromptsFile = %v, %v; want no problems", problems, err)
+	}
+	if _, err := lintPromptsFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("linting a missing file succeeded")
+	}
+}
+
+func TestLintCommand(t *testing.T) {
+	path := writeTestFile(t, "prompts.txt", DEFECTIVE_PROMPTS)
+	out, code := runGame(t, "", "-lint", "-prompts-file", path)
+	if code != 1 || strings.Count(out, path+":") != 6 || strings.Contains(out, msg("times-up")) {
+		t.Errorf("-lint exited %d, want 1 and six problems without playing:\n%s", code, out)
+	}
+
+	out, code = runGame(t, "", "-lint-json", "-prompts-file", path)
+	var problems []lintProblem
+	if err := json.Unmarshal([]byte(out), &problems); err != nil || code != 1 || len(problems) != 6 || problems[0] != (lintProblem{path, 3, "blank line"}) {
+		t.Errorf("-lint-json exited %d with %v, %v:\n%s", code, problems, err, out)
+	}
+
+	if out, code := runGame(t, "", "-lint", "-prompts-file", writeTestFile(t, "fine.txt", "Animals\nBands\n")); code != 0 || out != "" {
+		t.Errorf("-lint of a clean file exited %d:\n%s", code, out)
+	}
+	if _, code := runGame(t, "", "-lint", "-prompts-file", filepath.Join(t.TempDir(), "missing.txt")); code != 2 {
+		t.Errorf("-lint of a missing file exited %d, want 2", code)
+	}
+}
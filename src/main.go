@@ -1,87 +1,713 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"log"
-	"math/rand"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
 var (
 	PROMPTS_PATH                    = "./scattergories.txt"
+	PROMPTS_ENV                     = "SCATTERGORIES_PROMPTS"
+	PROMPTS_URL                     = ""
+	PROMPTS_CACHE                   = ""
 	LETTERS                         = []rune("ABCDEFGHIJKLMNOPRSTW")
 	NUM_PROMPTS                     = 12
+	MAX_PROMPTS                     = 12
 	SECONDS_PER_ROUND time.Duration = 180 * time.Second
-	RESOLUTION        time.Duration = 100 * time.Millisecond
+	RESOLUTION        time.Duration = time.Second
 	SEP                             = "==="
+	LETTER_FORMAT                   = "Letter: %s"
+	PROMPT_FORMAT                   = "  %d.\t%s"
+	TIMER_FORMAT                    = "Remaining time: %s"
+	TIME_DISPLAY                    = "mm ss"
+	ELAPSED_FORMAT                  = "Elapsed time: %s"
+	BOTH_FORMAT                     = "Elapsed %s / Remaining %s"
+	REPEAT_LETTERS                  = false
+	LETTERS_PER_ROUND               = 1
+	QUIT_WINDOW       time.Duration = 2 * time.Second
+	DEDUP_IGNORE_CASE               = false
+	PLAYERS                         = []string{}
+	SKIP_ARTICLES                   = false
+	ARTICLES                        = []string{"the", "a", "an"}
+	SCORING                         = ScoringRules{Unique: 1}
+	NORMALIZE                       = Normalization{Case: true, Space: true}
+	STANDINGS_PATH                  = ""
+	EXPORT_PATH                     = ""
+	ANSWERS_DIR                     = ""
+	HISTORY_PATH                    = ""
+	BAR_WIDTH                       = 0
+	BELLS                           = 1
+	BELL_GAP          time.Duration = 300 * time.Millisecond
+	SILENT                          = false
+	WARN_BEEPS                      = false
+	BEEP_AT                         = []time.Duration{30 * time.Second, 10 * time.Second}
+	WARN_AT                         = []time.Duration{}
+	COUNTDOWN                       = 3
+	EXTEND_KEY                      = "+"
+	EXTEND_BY         time.Duration = 30 * time.Second
+	SKIP_KEY                        = "s"
+	REDRAW_KEY                      = "r"
+	UNDO_KEY                        = "u"
+	UNDO_WINDOW       time.Duration = 10 * time.Second
+	REPLAY_KEY                      = "a"
+	RECALL_KEY                      = "l"
+	PAUSE_KEY                       = "p"
+	VERBOSE_INTRO                   = false
+	DISCARD_REDRAWN                 = false
+	QUIT_KEY                        = "q"
+	TUI                             = false
+	NO_SUMMARY                      = false
+	SERVE_ADDR                      = ""
+	API_TOKEN                       = ""
+	HOST_ADDR                       = ""
+	CONNECT_ADDR                    = ""
+	SHOW_ELAPSED                    = false
+	SHOW_BOTH                       = false
+	PACKS_DIR                       = ""
+	PACK                            = ""
+	TAGS                            = []string{}
+	ROUNDS                          = 0
+	NO_WAIT                         = false
+	NO_WAIT_DURATION  time.Duration = time.Second
+	SEED                            = time.Now().UnixNano()
+	CONFIG_PATH                     = ""
+	DIFFICULTY                      = "normal"
+	LANGUAGE                        = "en"
+	LINT                            = false
+	NOTIFY                          = false
+	STAGGER           time.Duration = 0
+	AUTO_REST         time.Duration = 0
+	MAX_ROUNDS                      = 0
+	EMIT_JSON                       = false
+	SUDDEN_DEATH                    = false
+	START_TIMEOUT     time.Duration = 0
+	SHEETS                          = false
+	PLAN                            = 0
+	NO_LETTER                       = false
+	LETTER_LAST                     = false
+	PICK_LETTER                     = false
+	HOST_PREVIEW                    = false
+	MAX_ANSWERS                     = 1
+	TITLE                           = ""
+	FIT_PROMPTS                     = false
+	BLITZ                           = false
+	SCORER                          = "standard"
+	TIEBREAK                        = "alphabetical"
+	TITLE_DATE                      = ""
+	SHEET_TIME        time.Duration = 2 * time.Minute
+	NO_MEMORY                       = false
+	PRACTICE_LOOP                   = false
+	LINT_JSON                       = false
+	LETTER_WEIGHTS                  = map[rune]float64{}
+	COLOR                           = false
+	LOG_LEVEL                       = slog.LevelWarn
+	DICTIONARY        Dictionary    = nil
+	STRICT_DICTIONARY               = false
+	WHOLE_PHRASES                   = false
+	HINTS                           = false
+	QUIET                           = false
+	VOTE                            = false
+	VOTE_TIES_ACCEPT                = true
+	SESSION_PATH                    = ""
+	EXAMPLES                        = Examples{}
+	TEAMS                           = []Team{}
+	BUZZER                          = false
+	BUZZER_TIMEOUT    time.Duration = 30 * time.Second
+	TURNS                           = false
+	SPRINT                          = false
+	SPRINT_TIME       time.Duration = 20 * time.Second
+	TURN_BUDGET       time.Duration = 60 * time.Second
 )
 
-func getPrompts() ([]string, error) {
-	file, err := os.Open(PROMPTS_PATH)
-	if err != nil {
-		return []string{}, err
+// parseSeconds parses a comma-separated list of whole seconds.
+func parseSeconds(s string) ([]time.Duration, error) {
+	durations := []time.Duration{}
+	for _, item := range splitList(s) {
+		n, err := strconv.Atoi(item)
+		if err != nil || n <= 0 {
+			return []time.Duration{}, fmt.Errorf("%q is not a positive number of seconds", item)
+		}
+		durations = append(durations, time.Duration(n)*time.Second)
 	}
-	defer file.Close()
+	return durations, nil
+}
 
-	prompts := []string{}
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		prompts = append(prompts, scanner.Text())
+// parsePromptCount parses a number of prompts, "12", or a range of them,
+// "8-14", returning the smallest and largest counts allowed.
+func parsePromptCount(s string) (lo, hi int, err error) {
+	first, last, isRange := strings.Cut(s, "-")
+	lo, err = strconv.Atoi(strings.TrimSpace(first))
+	hi = lo
+	if err == nil && isRange {
+		hi, err = strconv.Atoi(strings.TrimSpace(last))
 	}
-	if err := scanner.Err(); err != nil {
-		return []string{}, err
+	switch {
+	case err != nil:
+		return 0, 0, fmt.Errorf("%q is not a number of prompts or a range like 8-14", s)
+	case lo <= 0:
+		return 0, 0, fmt.Errorf("%q: the number of prompts must be positive", s)
+	case hi < lo:
+		return 0, 0, fmt.Errorf("%q: the range runs backwards", s)
 	}
-	return prompts, nil
+	return lo, hi, nil
+}
+
+// lint checks the prompts file, or each pack in PACKS_DIR, printing any
+// problems found, and returns the exit status: 0 if there were none, 1 if
+// there were, and 2 if a file couldn't be read.
+func lint() int {
+	paths := []string{PROMPTS_PATH}
+	if PACKS_DIR != "" {
+		packs, err := findPacks(PACKS_DIR)
+		if err != nil {
+			log.Print(err)
+			return 2
+		}
+		paths = packPaths(packs)
+	}
+	problems := []lintProblem{}
+	for _, path := range paths {
+		more, err := lintPromptsFile(path)
+		if err != nil {
+			log.Printf("-lint: %v", err)
+			return 2
+		}
+		problems = append(problems, more...)
+	}
+	if err := printLint(os.Stdout, problems, LINT_JSON); err != nil {
+		log.Printf("-lint: %v", err)
+		return 2
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// setupLogging sends log/slog messages at level and above to stderr. Fatal
+// startup errors still go straight through the log package, so they show
+// whatever the level.
+func setupLogging(level slog.Level) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.LstdFlags)
 }
 
 func main() {
-	fmt.Println("Welcome to Scattergories!")
+	var err error
+	if path := os.Getenv(PROMPTS_ENV); path != "" {
+		PROMPTS_PATH = path
+	}
+	flag.StringVar(&PROMPTS_PATH, "prompts-file", PROMPTS_PATH, "file to load prompts from, one per line (env "+PROMPTS_ENV+")")
+	flag.StringVar(&PROMPTS_URL, "prompts-url", PROMPTS_URL, "URL to download the prompts from at startup, falling back to -prompts-file")
+	flag.StringVar(&PROMPTS_CACHE, "prompts-cache", PROMPTS_CACHE, "file to cache -prompts-url in for offline runs (default in the user cache directory)")
+	flag.DurationVar(&SECONDS_PER_ROUND, "duration", SECONDS_PER_ROUND, "length of each round, e.g. 2m or 90s")
+	flag.DurationVar(&RESOLUTION, "resolution", RESOLUTION, "how often to redraw the timer, e.g. 100ms for a smoother -bar; it still redraws as each second passes")
+	flag.DurationVar(&STAGGER, "stagger", STAGGER, "reveal the prompts one at a time over this much of the start of each round, at most half of it (0 shows them all at once)")
+	numPrompts := flag.String("prompts", strconv.Itoa(NUM_PROMPTS), "number of prompts per round, or a range like 8-14 to draw a random number each round")
+	flag.BoolVar(&FIT_PROMPTS, "fit-prompts", FIT_PROMPTS, "if there are fewer prompts than -prompts asks for, draw as many as there are instead of refusing to start")
+	flag.BoolVar(&DEDUP_IGNORE_CASE, "dedup-ignore-case", DEDUP_IGNORE_CASE, "treat prompts differing only in case as duplicates")
+	flag.Int64Var(&SEED, "seed", SEED, "random seed; share it to replay the same letters and prompts")
+	flag.IntVar(&LETTERS_PER_ROUND, "letters-per-round", LETTERS_PER_ROUND, "letters to draw each round; answers can start with any of them")
+	flag.BoolVar(&REPEAT_LETTERS, "repeat-letters", REPEAT_LETTERS, "draw each letter independently, allowing immediate repeats")
+	players := flag.String("players", "", "comma-separated player names; collects answers after each round")
+	teams := flag.String("teams", "", `teams to pool players' points into, e.g. "Red:Alice,Bob;Blue:Carol,Dan"; sets the players`)
+	flag.BoolVar(&SKIP_ARTICLES, "skip-articles", SKIP_ARTICLES, `ignore a leading "the", "a" or "an" when checking an answer's letter`)
+	flag.IntVar(&SCORING.Unique, "points", SCORING.Unique, "points for each valid answer no other player gave")
+	flag.IntVar(&SCORING.Duplicate, "duplicate-points", SCORING.Duplicate, "points for each valid answer another player also gave")
+	flag.IntVar(&SCORING.Alliteration, "alliteration-bonus", SCORING.Alliteration, "extra points for a unique answer of two or more words all starting with the letter")
+	normalize := flag.String("normalize", NORMALIZE.String(), "what to ignore when spotting duplicate answers, any of: case, space (runs of spaces), nospace (all spaces), punct, accents; or none")
+	flag.StringVar(&TIEBREAK, "tiebreak", TIEBREAK, "how to order players, and -teams, tied on points: alphabetical, duplicates for fewest duplicate answers, or prompt for a playoff prompt at the end (players only)")
+	flag.StringVar(&SCORER, "scorer", SCORER, "how answers score: standard, or words for -points per word of a unique answer starting with the letter")
+	flag.StringVar(&STANDINGS_PATH, "standings", STANDINGS_PATH, "JSON file to load standings from and save them to after each round")
+	flag.StringVar(&EXPORT_PATH, "export", EXPORT_PATH, "CSV file to write each round's answers to")
+	flag.StringVar(&ANSWERS_DIR, "answers-dir", ANSWERS_DIR, "directory to write a text file of each round's answers to, e.g. round03_B.txt")
+	flag.StringVar(&HISTORY_PATH, "history", HISTORY_PATH, "JSONL file to append a line to for each round played")
+	flag.IntVar(&BAR_WIDTH, "bar", BAR_WIDTH, "show a progress bar this many characters wide next to the timer (0 to hide)")
+	flag.IntVar(&BELLS, "bells", BELLS, "times to ring the terminal bell when time runs out")
+	flag.BoolVar(&SILENT, "silent", SILENT, "never ring the terminal bell")
+	flag.BoolVar(&WARN_BEEPS, "warn-beeps", WARN_BEEPS, "also ring the bell with 30 and 10 seconds left")
+	flag.IntVar(&COUNTDOWN, "countdown", COUNTDOWN, "seconds to count down before revealing the letter (0 to disable)")
+	flag.DurationVar(&EXTEND_BY, "extend", EXTEND_BY, "time added to the round when "+EXTEND_KEY+" is entered")
+	flag.BoolVar(&SHOW_ELAPSED, "elapsed", SHOW_ELAPSED, "show time elapsed instead of time remaining")
+	flag.BoolVar(&SHOW_BOTH, "both", SHOW_BOTH, "show time elapsed and time remaining side by side")
+	flag.StringVar(&PACKS_DIR, "packs-dir", PACKS_DIR, "directory of .txt prompt packs to choose from")
+	flag.StringVar(&PACK, "pack", PACK, "comma-separated packs from -packs-dir to play; asks if unset")
+	flag.IntVar(&ROUNDS, "rounds", ROUNDS, "play this many rounds back to back without waiting for enter, then exit (0 to play until quit)")
+	practice := flag.String("practice", "", "replay the rounds in this -history file instead of drawing new ones, showing the answers given before")
+	flag.BoolVar(&PRACTICE_LOOP, "practice-loop", PRACTICE_LOOP, "start -practice over from the first round after the last, instead of ending the game")
+	flag.BoolVar(&NO_MEMORY, "no-memory", NO_MEMORY, "don't remember recent letters across runs in ~/.scattergories-letters.json to open with different ones (a chosen -seed or -plan never does)")
+	flag.BoolVar(&EMIT_JSON, "emit-json", EMIT_JSON, "write a JSON event per line to stdout as rounds start, tick, end and score, moving everything else to stderr")
+	flag.BoolVar(&NO_LETTER, "no-letter", NO_LETTER, "play with prompts only: no letter is drawn and any answer counts")
+	flag.BoolVar(&LETTER_LAST, "letter-last", LETTER_LAST, "show each round's prompts first and reveal the letter, starting the clock, when enter is pressed")
+	flag.BoolVar(&HOST_PREVIEW, "host-preview", HOST_PREVIEW, "show the host the next round's letter on stderr while each round runs")
+	flag.BoolVar(&PICK_LETTER, "pick-letter", PICK_LETTER, "before each round, offer to play a letter of the players' choosing instead of the one drawn")
+	flag.IntVar(&MAX_ANSWERS, "answers-per-prompt", MAX_ANSWERS, "answers each player can give per prompt, separated by commas or one per line; each valid unique one scores")
+	flag.StringVar(&TITLE, "title", TITLE, `title for the game night, e.g. "Movie Night", shown in the banner and separators and saved with exports and history`)
+	flag.StringVar(&TITLE_DATE, "date", TITLE_DATE, "date to show and save with -title (default today, as YYYY-MM-DD)")
+	flag.BoolVar(&BLITZ, "blitz", BLITZ, "play a blitz: 10 back-to-back 30-second rounds of one prompt each (-rounds and -duration change them), then the combined standings")
+	flag.BoolVar(&VERBOSE_INTRO, "verbose-intro", VERBOSE_INTRO, "start with how to play and the settings in use, for new players")
+	flag.IntVar(&PLAN, "plan", PLAN, "print the letters and prompts of this many rounds for the -seed, then exit without playing")
+	flag.BoolVar(&SHEETS, "sheets", SHEETS, "have -players paste whole numbered answer sheets after each round instead of answering prompt by prompt")
+	flag.DurationVar(&SHEET_TIME, "sheet-time", SHEET_TIME, "time everyone shares to paste their -sheets in")
+	flag.DurationVar(&START_TIMEOUT, "start-timeout", START_TIMEOUT, "start the next round anyway if nobody chooses from the menu within this long, e.g. 1m for kiosks (0 waits forever)")
+	flag.BoolVar(&SUDDEN_DEATH, "sudden-death", SUDDEN_DEATH, "play the last of -rounds or -max-rounds at half the time for double points")
+	flag.IntVar(&MAX_ROUNDS, "max-rounds", MAX_ROUNDS, "end the game with its report after this many rounds (0 for no limit)")
+	flag.DurationVar(&AUTO_REST, "auto", AUTO_REST, "skip the menu and start each round on its own after resting this long, e.g. 15s, until Ctrl+C (0 waits for enter)")
+	flag.BoolVar(&NO_WAIT, "no-wait", NO_WAIT, "smoke-test mode: very short rounds and no countdown")
+	tags := flag.String("tags", "", "comma-separated tags; only prompts with one of them are used")
+	letters := flag.String("letters", string(LETTERS), "letters rounds can use")
+	preset := flag.String("letter-preset", "", "named letter set to play with: standard, all, vowel-heavy or consonants-only; -letters and -letter-weights override it")
+	exclude := flag.String("exclude", "", "letters to leave out of -letters, e.g. KQ")
+	weights := flag.String("letter-weights", "", "comma-separated LETTER=WEIGHT pairs to favor easier letters, e.g. S=3,K=0.5 (unlisted letters weigh 1)")
+	flag.StringVar(&SEP, "sep", SEP, "line printed before and after each round")
+	flag.StringVar(&LETTER_FORMAT, "letter-format", LETTER_FORMAT, "template for the letter line, given the letter (%s)")
+	flag.StringVar(&PROMPT_FORMAT, "prompt-format", PROMPT_FORMAT, "template for each prompt line, given its number (%d) and text (%s)")
+	flag.StringVar(&TIMER_FORMAT, "timer-format", TIMER_FORMAT, "template for the timer, given the time remaining (%s)")
+	flag.StringVar(&TIME_DISPLAY, "time-display", TIME_DISPLAY, msg("time-display"))
+	flag.StringVar(&ELAPSED_FORMAT, "elapsed-format", ELAPSED_FORMAT, "template for the timer with -elapsed, given the time elapsed (%s)")
+	flag.StringVar(&BOTH_FORMAT, "both-format", BOTH_FORMAT, "template for the timer with -both, given the time elapsed (%s) and remaining (%s)")
+	flag.BoolVar(&DISCARD_REDRAWN, "discard-redrawn", DISCARD_REDRAWN, "throw away a redrawn round's letter and prompts instead of returning them to the pools")
+	flag.BoolVar(&NO_SUMMARY, "no-summary", NO_SUMMARY, "don't recap each round after it ends")
+	flag.StringVar(&SERVE_ADDR, "serve", SERVE_ADDR, "address to serve the current round on over HTTP, e.g. :8080")
+	flag.StringVar(&API_TOKEN, "token", API_TOKEN, "bearer token required to start or end rounds through the -serve API")
+	flag.StringVar(&HOST_ADDR, "host", HOST_ADDR, "address to broadcast rounds on for -connect clients, e.g. :9000")
+	flag.StringVar(&CONNECT_ADDR, "connect", CONNECT_ADDR, "follow the rounds of a -host at this address instead of running a game")
+	flag.BoolVar(&BUZZER, "buzzer", BUZZER, "show prompts one at a time; the first valid answer from -players takes each")
+	flag.DurationVar(&BUZZER_TIMEOUT, "buzzer-timeout", BUZZER_TIMEOUT, "time to claim each prompt with -buzzer before it's skipped")
+	flag.BoolVar(&SPRINT, "sprint", SPRINT, "category sprint: show prompts one at a time, each on its own -sprint-time clock")
+	flag.DurationVar(&SPRINT_TIME, "sprint-time", SPRINT_TIME, "time on the clock for each prompt with -sprint")
+	flag.BoolVar(&TURNS, "turns", TURNS, "-players take turns answering each prompt, each with their own clock")
+	flag.DurationVar(&TURN_BUDGET, "budget", TURN_BUDGET, "time on each player's clock per round with -turns")
+	flag.TextVar(&LOG_LEVEL, "log-level", LOG_LEVEL, "least severe log messages to write to stderr: debug, info, warn or error")
+	dictionary := flag.String("dictionary", "", "word list to check answers against, one word or phrase per line")
+	flag.BoolVar(&STRICT_DICTIONARY, "strict-dictionary", STRICT_DICTIONARY, "score nothing for answers missing from -dictionary")
+	flag.BoolVar(&WHOLE_PHRASES, "dictionary-phrases", WHOLE_PHRASES, "look up whole answers in -dictionary rather than their first word")
+	flag.BoolVar(&HINTS, "hints", HINTS, "suggest an example answer to each prompt in the round summary")
+	examples := flag.String("examples", "", `file of example answers for -hints, one "prompt|answer,answer" per line`)
+	flag.BoolVar(&QUIET, "quiet", QUIET, "don't show the running timer, just the round and when time's up")
+	flag.BoolVar(&VOTE, "vote", VOTE, "have -players vote on each valid answer before scoring; rejected answers score nothing")
+	flag.BoolVar(&VOTE_TIES_ACCEPT, "vote-ties-accept", VOTE_TIES_ACCEPT, "accept answers when the -vote is tied (false rejects them)")
+	flag.StringVar(&SESSION_PATH, "session", SESSION_PATH, "JSON file to save the game to after each round, for -resume")
+	resume := flag.String("resume", "", "pick up the game saved in this -session file, and keep saving to it")
+	tui := flag.Bool("tui", false, "redraw the whole screen during rounds instead of a single timer line")
+	color := flag.Bool("color", false, "colorize output when writing to a terminal")
+	flag.StringVar(&DIFFICULTY, "difficulty", DIFFICULTY, "preset round length, prompt count and letters: easy, normal or hard; other flags override it")
+	flag.StringVar(&LANGUAGE, "lang", LANGUAGE, "language for messages and, where a NAME.LANG.txt file exists, prompts: en or es")
+	flag.BoolVar(&NOTIFY, "notify", NOTIFY, "show a desktop notification when each round ends, if osascript or notify-send is installed")
+	flag.BoolVar(&LINT, "lint", LINT, "check the prompts file, or every pack in -packs-dir, for mistakes and exit, non-zero if any are found")
+	flag.BoolVar(&LINT_JSON, "lint-json", LINT_JSON, "like -lint, but report the problems as JSON")
+	flag.StringVar(&CONFIG_PATH, "config", CONFIG_PATH, "JSON file of settings keyed by flag name; flags override it")
+	warn := flag.String("warn", "", "comma-separated seconds-left marks to announce, e.g. 60,30,10")
+	flag.Parse()
+	// What was given on the command line, before a config file or preset
+	// sets any more flags
+	onCommandLine := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { onCommandLine[f.Name] = true })
+	if CONFIG_PATH != "" {
+		if err := applyConfig(flag.CommandLine, CONFIG_PATH); err != nil {
+			log.Fatalf("-config: %v", err)
+		}
+	}
+	if BLITZ {
+		if err := applyPreset(flag.CommandLine, BLITZ_PRESET); err != nil {
+			log.Fatalf("-blitz: %v", err)
+		}
+	}
+	if err := applyDifficulty(flag.CommandLine, DIFFICULTY); err != nil {
+		log.Fatalf("-difficulty: %v", err)
+	}
+	setupLogging(LOG_LEVEL)
+	if err := checkLanguage(LANGUAGE); err != nil {
+		log.Fatalf("-lang: %v", err)
+	}
+	if err := localizeFormats(flag.CommandLine); err != nil {
+		log.Fatalf("-lang: %v", err)
+	}
+	if LINT || LINT_JSON {
+		os.Exit(lint())
+	}
+	var letterSource LetterSource = letterSpec{*letters, *exclude, *weights}
+	if *preset != "" {
+		spec, ok := LETTER_PRESETS[*preset]
+		if !ok {
+			log.Fatalf("-letter-preset: no preset named %q", *preset)
+		}
+		// Letter flags given alongside it on the command line override the
+		// preset's own, but not ones another preset or a config file set
+		if onCommandLine["letters"] {
+			spec.set = *letters
+		}
+		if onCommandLine["letter-weights"] {
+			spec.weights = *weights
+		}
+		spec.exclude = *exclude
+		letterSource = spec
+	}
+	if LETTERS, LETTER_WEIGHTS, err = letterSource.Letters(); err != nil {
+		log.Fatal(err)
+	}
+	if len(LETTER_WEIGHTS) > 0 && !slices.ContainsFunc(LETTERS, func(r rune) bool { return letterWeight(LETTER_WEIGHTS, r) > 0 }) {
+		log.Fatal("-letter-weights: every letter weighs zero")
+	}
+	if LETTERS_PER_ROUND <= 0 || LETTERS_PER_ROUND > len(LETTERS) {
+		log.Fatalf("-letters-per-round must be between 1 and the %d letters in play, got %d", len(LETTERS), LETTERS_PER_ROUND)
+	}
+	if weighted := slices.DeleteFunc(slices.Clone(LETTERS), func(r rune) bool { return letterWeight(LETTER_WEIGHTS, r) <= 0 }); len(LETTER_WEIGHTS) > 0 && len(weighted) < LETTERS_PER_ROUND {
+		log.Fatalf("-letter-weights: only %d letters have any weight, but -letters-per-round is %d", len(weighted), LETTERS_PER_ROUND)
+	}
+	for _, template := range []struct{ name, format, kinds string }{
+		{"-letter-format", LETTER_FORMAT, "s"},
+		{"-prompt-format", PROMPT_FORMAT, "ds"},
+		{"-timer-format", TIMER_FORMAT, "s"},
+		{"-elapsed-format", ELAPSED_FORMAT, "s"},
+		{"-both-format", BOTH_FORMAT, "ss"},
+	} {
+		if err := checkFormat(template.format, template.kinds); err != nil {
+			log.Fatalf("%s: %v", template.name, err)
+		}
+	}
+	PLAYERS = splitList(*players)
+	if TEAMS, err = parseTeams(*teams); err != nil {
+		log.Fatalf("-teams: %v", err)
+	}
+	if len(TEAMS) > 0 && len(PLAYERS) > 0 {
+		log.Fatal("-teams sets the players; drop -players")
+	} else if len(TEAMS) > 0 {
+		PLAYERS = teamPlayers(TEAMS)
+	}
+	out := os.Stdout // where players read the game
+	if EMIT_JSON {
+		out = os.Stderr
+	}
+	COLOR = *color && isTerminal(out)
+	TUI = *tui && isTerminal(out) && os.Getenv("TERM") != "dumb"
+	TAGS = splitList(strings.ToLower(*tags))
+	if *dictionary != "" {
+		if DICTIONARY, err = loadDictionary(*dictionary); err != nil {
+			log.Fatalf("-dictionary: %v", err)
+		}
+		slog.Info("loaded dictionary", "path", *dictionary, "words", len(DICTIONARY))
+	}
+	if *examples != "" {
+		if EXAMPLES, err = loadExamples(*examples); err != nil {
+			log.Fatalf("-examples: %v", err)
+		}
+	} else if HINTS {
+		log.Fatal("-hints needs -examples")
+	}
+	if NUM_PROMPTS, MAX_PROMPTS, err = parsePromptCount(*numPrompts); err != nil {
+		log.Fatalf("-prompts: %v", err)
+	}
+	if NORMALIZE, err = parseNormalization(*normalize); err != nil {
+		log.Fatalf("-normalize: %v", err)
+	}
+	if !slices.Contains(TIME_DISPLAYS, TIME_DISPLAY) {
+		log.Fatalf("-time-display: %q isn't %s", TIME_DISPLAY, strings.Join(TIME_DISPLAYS, ", "))
+	}
+	if !slices.Contains(TIEBREAKS, TIEBREAK) {
+		log.Fatalf("-tiebreak: %q isn't %s", TIEBREAK, strings.Join(TIEBREAKS, ", "))
+	}
+	if TIEBREAK == "prompt" && len(TEAMS) > 0 {
+		log.Fatal("-tiebreak prompt plays off tied players, not teams; use alphabetical or duplicates with -teams")
+	}
+	scorer, err := newScorer(SCORER, SCORING)
+	if err != nil {
+		log.Fatalf("-scorer: %v", err)
+	}
+	if WARN_AT, err = parseSeconds(*warn); err != nil {
+		log.Fatalf("-warn: %v", err)
+	}
+	if NO_WAIT {
+		SECONDS_PER_ROUND = NO_WAIT_DURATION
+		COUNTDOWN = 0
+	}
+	if TITLE_DATE != "" && TITLE == "" {
+		log.Fatal("-date needs -title")
+	}
+	if TITLE != "" {
+		if TITLE_DATE == "" {
+			TITLE_DATE = time.Now().Format(time.DateOnly)
+		}
+		SEP = fmt.Sprintf("%s %s %s", SEP, TITLE, SEP)
+	}
 
-	// Shuffle inputs
-	prompts, err := getPrompts()
+	fmt.Fprintln(out, msg("welcome"))
+	if TITLE != "" {
+		fmt.Fprintln(out, sessionInfo{TITLE, TITLE_DATE})
+	}
+	if CONNECT_ADDR != "" {
+		if err := joinGame(CONNECT_ADDR, out); err != nil {
+			log.Fatalf("-connect: %v", err)
+		}
+		return
+	}
+
+	lines := readLines(os.Stdin)
+
+	// Load and validate inputs
+	source := PROMPTS_PATH
+	sources := []PromptSource{fileSource(localizedPath(PROMPTS_PATH))}
+	if PROMPTS_URL != "" {
+		if PROMPTS_CACHE == "" {
+			if PROMPTS_CACHE, err = defaultCachePath(PROMPTS_URL); err != nil {
+				log.Fatalf("-prompts-url: %v", err)
+			}
+		}
+		source = PROMPTS_URL
+		sources = []PromptSource{urlSource{PROMPTS_URL, PROMPTS_CACHE, sources[0]}}
+	}
+	if PACKS_DIR != "" {
+		packs, err := findPacks(PACKS_DIR)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var chosen []Pack
+		if PACK != "" {
+			if chosen, err = selectPacks(packs, splitList(PACK)); err != nil {
+				log.Fatalf("-pack: %v", err)
+			}
+		} else {
+			var ok bool
+			if chosen, ok = choosePacks(packs, lines, out); !ok {
+				return
+			}
+		}
+		paths := packPaths(chosen)
+		for i, path := range paths {
+			paths[i] = localizedPath(path)
+		}
+		sources = fileSources(paths)
+		source = PACKS_DIR
+	} else if PACK != "" {
+		log.Fatal("-pack needs -packs-dir")
+	}
+	loaded, err := getPrompts(sources...)
 	if err != nil {
 		log.Fatal(err)
 	}
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(LETTERS), func(i, j int) {
-		LETTERS[i], LETTERS[j] = LETTERS[j], LETTERS[i]
-	})
-	rand.Shuffle(len(prompts), func(i, j int) {
-		prompts[i], prompts[j] = prompts[j], prompts[i]
-	})
-
-	// Loop
-	var letter rune
-	var prompt string
-	for {
-		// Print instructions and wait for enter
-		fmt.Print("Press enter to start a round.")
-		fmt.Scanln()
-
-		// Show letter and prompts
-		fmt.Println(SEP)
-		letter, LETTERS = LETTERS[0], LETTERS[1:]
-		fmt.Printf("Letter: %s\n", string(letter))
-		fmt.Println("Prompts:")
-		for i := 0; i < NUM_PROMPTS; i++ {
-			prompt, prompts = prompts[0], prompts[1:]
-			fmt.Printf("  %d.\t%s\n", i, prompt)
-		}
-		fmt.Println("")
-
-		// Show timer until round ends or is interrupted
-		start := time.Now()
-		current := start
-		target := start.Add(SECONDS_PER_ROUND)
-		for current.Before(target) {
-			delta := int(target.Sub(current).Seconds())
-			fmt.Printf("\rRemaining time: %dm%ds", delta/60, delta%60)
-			time.Sleep(RESOLUTION)
-			current = time.Now()
-		}
-		fmt.Println("Time's up!")
-		fmt.Println(SEP)
+	prompts, weighting := promptTexts(loaded), promptWeights(loaded)
+	if SECONDS_PER_ROUND <= 0 {
+		log.Fatalf("-duration must be positive, got %s", SECONDS_PER_ROUND)
+	}
+	if STAGGER < 0 || STAGGER > SECONDS_PER_ROUND/2 {
+		log.Fatalf("-stagger must be between 0 and half of -duration (%s), got %s", SECONDS_PER_ROUND/2, STAGGER)
+	}
+	var why string
+	if RESOLUTION, why = clampResolution(RESOLUTION, SECONDS_PER_ROUND); why != "" {
+		slog.Warn("adjusted -resolution", "reason", why)
+	}
+	if AUTO_REST < 0 {
+		log.Fatalf("-auto can't be negative, got %s", AUTO_REST)
+	}
+	if SHOW_ELAPSED && SHOW_BOTH {
+		log.Fatal("-elapsed and -both can't be combined")
+	}
+	if MAX_ROUNDS < 0 {
+		log.Fatalf("-max-rounds can't be negative, got %d", MAX_ROUNDS)
+	}
+	if PLAN < 0 {
+		log.Fatalf("-plan can't be negative, got %d", PLAN)
+	}
+	if SHEET_TIME <= 0 {
+		log.Fatalf("-sheet-time must be positive, got %s", SHEET_TIME)
+	}
+	if START_TIMEOUT < 0 {
+		log.Fatalf("-start-timeout can't be negative, got %s", START_TIMEOUT)
+	}
+	if LETTER_LAST && (NO_LETTER || STAGGER > 0 || BUZZER || TURNS) {
+		log.Fatal("-letter-last can't be combined with -no-letter, -stagger, -buzzer or -turns")
+	}
+	if MAX_ANSWERS < 1 {
+		log.Fatalf("-answers-per-prompt must be at least 1, got %d", MAX_ANSWERS)
+	}
+	if MAX_ANSWERS > 1 && (SHEETS || BUZZER || TURNS) {
+		log.Fatal("-answers-per-prompt can't be combined with -sheets, -buzzer or -turns")
+	}
+	if HOST_PREVIEW && NO_LETTER {
+		log.Fatal("-host-preview can't be combined with -no-letter")
+	}
+	if PICK_LETTER && NO_LETTER {
+		log.Fatal("-pick-letter can't be combined with -no-letter")
+	}
+	if SUDDEN_DEATH && ROUNDS == 0 && MAX_ROUNDS == 0 {
+		log.Fatal("-sudden-death needs -rounds or -max-rounds to know which round is last")
+	}
+	if ROUNDS < 0 {
+		log.Fatalf("-rounds can't be negative, got %d", ROUNDS)
+	}
+	if BUZZER && len(PLAYERS) == 0 {
+		log.Fatal("-buzzer needs -players")
+	}
+	if TURNS && len(PLAYERS) == 0 {
+		log.Fatal("-turns needs -players")
+	}
+	if TURNS && BUZZER {
+		log.Fatal("-turns and -buzzer can't be combined")
+	}
+	if TURN_BUDGET <= 0 {
+		log.Fatalf("-budget must be positive, got %s", TURN_BUDGET)
+	}
+	if SPRINT && (BUZZER || TURNS || SHEETS || TUI || STAGGER > 0 || LETTER_LAST) {
+		log.Fatal("-sprint can't be combined with -buzzer, -turns, -sheets, -tui, -stagger or -letter-last")
+	}
+	if SPRINT_TIME <= 0 {
+		log.Fatalf("-sprint-time must be positive, got %s", SPRINT_TIME)
+	}
+	if BUZZER_TIMEOUT <= 0 {
+		log.Fatalf("-buzzer-timeout must be positive, got %s", BUZZER_TIMEOUT)
+	}
+
+	// Check there are enough prompts left after filtering to fill a round
+	available, why := len(prompts), "were loaded from "+source
+	if len(TAGS) > 0 {
+		why = fmt.Sprintf("in %s are tagged %s", source, strings.Join(TAGS, " or "))
+	}
+	if MAX_PROMPTS > available {
+		if !FIT_PROMPTS || available == 0 {
+			log.Fatalf("-prompts is %s but only %d prompts %s; -fit-prompts would draw fewer", *numPrompts, available, why)
+		}
+		slog.Warn("too few prompts for -prompts, drawing fewer", "prompts", *numPrompts, "available", available)
+		NUM_PROMPTS, MAX_PROMPTS = min(NUM_PROMPTS, available), available
 	}
+
+	// Shuffle inputs
+	game := NewGame(Config{
+		Duration:      SECONDS_PER_ROUND,
+		NumPrompts:    NUM_PROMPTS,
+		MaxPrompts:    MAX_PROMPTS,
+		Resolution:    RESOLUTION,
+		Stagger:       STAGGER,
+		NoLetter:      NO_LETTER,
+		LetterLast:    LETTER_LAST,
+		Letters:       LETTERS,
+		RepeatLetters: REPEAT_LETTERS,
+		LettersPer:    LETTERS_PER_ROUND,
+		LetterWeights: LETTER_WEIGHTS,
+		PromptWeights: weighting,
+		Rounds:        ROUNDS,
+		Rest:          AUTO_REST,
+		MaxRounds:     MAX_ROUNDS,
+		SuddenDeath:   SUDDEN_DEATH,
+		StartTimeout:  START_TIMEOUT,
+	}, prompts, SEED, lines, out)
+	if *resume != "" {
+		session, err := loadSession(*resume)
+		if err != nil {
+			log.Fatalf("-resume: %v", err)
+		}
+		if err := game.Restore(session); err != nil {
+			log.Fatalf("-resume: %v", err)
+		}
+		if SESSION_PATH == "" {
+			SESSION_PATH = *resume
+		}
+		fmt.Fprintf(out, msg("resuming")+"\n", game.Played)
+	}
+	if *practice != "" {
+		if game.Practice, err = loadHistory(*practice); err != nil {
+			log.Fatalf("-practice: %v", err)
+		}
+		fmt.Fprintf(out, msg("practicing")+"\n", len(game.Practice), *practice)
+	}
+	// The memory reorders the first letter pool, so a game replayed from a
+	// chosen -seed, or planned with -plan, leaves it out to draw as the seed
+	// says
+	seeded := false
+	flag.Visit(func(f *flag.Flag) { seeded = seeded || f.Name == "seed" })
+	if !NO_MEMORY && *practice == "" && !seeded && PLAN == 0 {
+		if path, err := defaultMemoryPath(); err != nil {
+			slog.Warn("can't remember letters across runs", "err", err)
+		} else {
+			game.Memory = loadLetterMemory(path)
+			if *resume == "" {
+				game.Avoid([]rune(game.Memory.Recent))
+			}
+		}
+	}
+	if VERBOSE_INTRO {
+		game.Intro(out)
+	} else {
+		fmt.Fprintf(out, msg("seed")+"\n", game.Seed)
+	}
+	if PLAN > 0 {
+		game.Plan(out, PLAN)
+		return
+	}
+
+	// Ctrl+C ends a round early; a second one quits, as a STOP_SIGNAL does,
+	// through the same shutdown as the game ending
+	stop := &shutdown{game: game}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, append(STOP_SIGNALS, os.Interrupt)...)
+	in := &interrupter{out: out, onQuit: func() int {
+		code := stop.run()
+		game.Report()
+		return code
+	}}
+	go in.listen(sigs)
+	if EXTEND_SIGNAL != nil {
+		// SIGUSR1 adds time and SIGUSR2 ends the round, for headless games
+		control := make(chan os.Signal, 1)
+		signal.Notify(control, EXTEND_SIGNAL, END_SIGNAL)
+		go game.listenControl(control, in)
+	}
+
+	game.Scorer = scorer
+	if EMIT_JSON {
+		game.Events = newEventStream(os.Stdout)
+	}
+	if NOTIFY {
+		game.Notifier = newNotifier()
+	}
+	if HOST_PREVIEW {
+		game.Preview = os.Stderr
+	}
+	if EXPORT_PATH != "" {
+		if game.Exporter, err = newCSVExporter(EXPORT_PATH, sessionInfo{TITLE, TITLE_DATE}); err != nil {
+			log.Fatalf("creating export file: %v", err)
+		}
+		stop.add("export file", game.Exporter)
+	}
+	if HISTORY_PATH != "" {
+		if game.History, err = openHistoryLog(HISTORY_PATH, sessionInfo{TITLE, TITLE_DATE}); err != nil {
+			log.Fatalf("opening history file: %v", err)
+		}
+		stop.add("history file", game.History)
+	}
+	if STANDINGS_PATH != "" && *resume == "" {
+		game.Board = loadScoreboard(STANDINGS_PATH)
+	}
+
+	if SERVE_ADDR != "" {
+		server := newServer(SERVE_ADDR, game, in)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("serving", "addr", SERVE_ADDR, "err", err)
+			}
+		}()
+		stop.add("server", server)
+		fmt.Fprintf(out, msg("serving")+"\n", SERVE_ADDR)
+	}
+
+	if HOST_ADDR != "" {
+		h, err := listenHost(HOST_ADDR, game)
+		if err != nil {
+			log.Fatalf("-host: %v", err)
+		}
+		stop.add("host", h)
+		fmt.Fprintf(out, msg("hosting")+"\n", HOST_ADDR)
+	}
+
+	game.Run(in)
+	os.Exit(stop.run())
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestMain runs the game instead of the tests when RUN_GAME_ENV is set, so
+// runGame can test it as a separate process.
+func TestMain(m *testing.M) {
+	if os.Getenv(RUN_GAME_ENV) != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+const RUN_GAME_ENV = "SCATTERGORIES_RUN_GAME"
+
+// runGame runs the game with args and input, in a home directory of its own
+// so it remembers nothing from other runs, and returns everything it wrote
+// and its exit code.
+func runGame(t *testing.T, input string, args ...string) (string, int) {
+	t.Helper()
+	return runGameIn(t, t.TempDir(), input, args...)
+}
+
+// runGameIn is runGame with home as the home directory.
+func runGameIn(t *testing.T, home, input string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), RUN_GAME_ENV+"=1", "HOME="+home)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return string(out), exit.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), 0
+}
+
+func TestHeadlessRounds(t *testing.T) {
+	out, code := runGame(t, "", "-rounds", "2", "-no-wait", "-seed", "7")
+	if code != 0 {
+		t.Fatalf("exited %d:\n%s", code, out)
+	}
+	if n := strings.Count(out, "Time's up!"); n != 2 {
+		t.Errorf("played %d rounds, want 2:\n%s", n, out)
+	}
+	if strings.Contains(out, "Press enter to start a round") {
+		t.Errorf("waited for enter between unattended rounds:\n%s", out)
+	}
+}
+
+func TestMaxRoundsEndsTheGame(t *testing.T) {
+	// Three rounds from two letters and three prompts take reshuffles
+	path := writeTestFile(t, "prompts.txt", "Animals\nBands\nCars\n")
+	out, code := runGame(t, "", "-max-rounds", "3", "-auto", "10ms", "-no-wait", "-letters", "AB", "-prompts", "2", "-prompts-file", path)
+	if code != 0 {
+		t.Fatalf("exited %d:\n%s", code, out)
+	}
+	if n := strings.Count(out, "Time's up!"); n != 3 {
+		t.Errorf("played %d rounds, want 3:\n%s", n, out)
+	}
+	if !strings.Contains(out, "Rounds played: 3") {
+		t.Errorf("didn't report 3 rounds:\n%s", out)
+	}
+}
+
+func TestPlanFlag(t *testing.T) {
+	path := writeTestFile(t, "prompts.txt", "Animals\nBands\nCars\n")
+	plan := func() string {
+		out, code := runGame(t, "", "-plan", "3", "-seed", "7", "-letters", "AB", "-prompts", "2", "-prompts-file", path)
+		if code != 0 {
+			t.Fatalf("exited %d:\n%s", code, out)
+		}
+		return out
+	}
+	out := plan()
+	if !strings.Contains(out, "Round 3: ") || strings.Contains(out, "Round 4") || strings.Contains(out, "Main menu") {
+		t.Errorf("didn't just plan 3 rounds:\n%s", out)
+	}
+	if again := plan(); again != out {
+		t.Errorf("planned\n%s\nthen, with the same seed,\n%s", out, again)
+	}
+}
+
+func TestParsePromptCount(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		lo, hi int
+		ok     bool
+	}{
+		{"12", 12, 12, true},
+		{"8-14", 8, 14, true},
+		{" 8 - 14 ", 8, 14, true},
+		{"5-5", 5, 5, true},
+		{"", 0, 0, false},
+		{"many", 0, 0, false},
+		{"8-", 0, 0, false},
+		{"-3", 0, 0, false},
+		{"0", 0, 0, false},
+		{"14-8", 0, 0, false},
+		{"8-14-20", 0, 0, false},
+	} {
+		lo, hi, err := parsePromptCount(test.in)
+		if lo != test.lo || hi != test.hi || (err == nil) != test.ok {
+			t.Errorf("parsePromptCount(%q) = %d, %d, %v; want %d, %d, ok %v", test.in, lo, hi, err, test.lo, test.hi, test.ok)
+		}
+	}
+}
+
+func TestTooFewPrompts(t *testing.T) {
+	// Four lines, but only three prompts once the repeat is dropped
+	path := writeTestFile(t, "prompts.txt", "Animals|easy\nBands|easy\nCars\nBands|easy\n")
+	for _, test := range []struct {
+		name string
+		args []string
+		code int
+		says string
+	}{
+		{"refuses", []string{"-prompts", "4"}, 1, "-prompts is 4 but only 3 prompts were loaded from"},
+		{"refuses after filtering", []string{"-prompts", "3", "-tags", "easy"}, 1, "-prompts is 3 but only 2 prompts in " + path + " are tagged easy"},
+		{"refuses a range", []string{"-prompts", "2-5"}, 1, "-prompts is 2-5 but only 3 prompts"},
+		{"fits", []string{"-prompts", "4", "-fit-prompts"}, 0, "too few prompts for -prompts"},
+		{"enough", []string{"-prompts", "3"}, 0, "3.\t"},
+	} {
+		args := append([]string{"-plan", "2", "-prompts-file", path}, test.args...)
+		out, code := runGame(t, "", args...)
+		if code != test.code || !strings.Contains(out, test.says) {
+			t.Errorf("%s: exited %d, want %d saying %q:\n%s", test.name, code, test.code, test.says, out)
+		}
+		// Fitting draws every prompt there is each round
+		if code == 0 && (strings.Count(out, "  3.\t") != 2 || strings.Contains(out, "  4.\t")) {
+			t.Errorf("%s: didn't draw all three prompts each round:\n%s", test.name, out)
+		}
+	}
+}
+
+func TestVerboseIntroShowsResolvedSettings(t *testing.T) {
+	config := writeTestFile(t, "config.json", `{"duration": "90s", "letters": "ABC"}`)
+	args := []string{"-config", config, "-prompts", "5-8", "-rounds", "4", "-seed", "42", "-plan", "1"}
+	out, code := runGame(t, "", append(args, "-verbose-intro")...)
+	if code != 0 {
+		t.Fatalf("exited %d:\n%s", code, out)
+	}
+	for _, want := range []string{msg("rules"), "Round length: 1m30s", "Prompts per round: 5-8", "Letters: ABC", "Rounds: 4", "Seed: 42"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("intro doesn't say %q:\n%s", want, out)
+		}
+	}
+	out, _ = runGame(t, "", args...)
+	if strings.Contains(out, msg("rules")) || strings.Contains(out, "Round length:") || !strings.Contains(out, "Seed: 42") {
+		t.Errorf("without -verbose-intro, want just the seed:\n%s", out)
+	}
+}
+
+func TestBadTimeDisplay(t *testing.T) {
+	out, code := runGame(t, "", "-time-display", "hh:mm", "-plan", "1")
+	if code != 1 || !strings.Contains(out, `-time-display: "hh:mm" isn't mm ss, m:ss, seconds`) {
+		t.Errorf("exited %d, want 1 naming the choices:\n%s", code, out)
+	}
+}
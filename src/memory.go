@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// MEMORY_SIZE is how many recently played letters the letter memory keeps.
+const MEMORY_SIZE = 10
+
+// letterMemory remembers the letters played most recently across runs, so a
+// new game can put them at the back of its first letter pool.
+type letterMemory struct {
+	path   string
+	Recent string `json:"recent"` // oldest first
+}
+
+// defaultMemoryPath is the dotfile in the home directory the letter memory
+// is kept in.
+func defaultMemoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".scattergories-letters.json"), nil
+}
+
+// loadLetterMemory reads the letter memory at path. If there's none yet, or
+// it can't be read, the game starts with an empty one rather than failing.
+func loadLetterMemory(path string) *letterMemory {
+	m := &letterMemory{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m
+	} else if err != nil {
+		slog.Warn("can't read letter memory, starting afresh", "path", path, "err", err)
+		return m
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		slog.Warn("letter memory is corrupt, starting afresh", "path", path, "err", err)
+		m.Recent = ""
+	}
+	return m
+}
+
+// Remember adds letters as the most recently played, forgetting the oldest
+// beyond MEMORY_SIZE.
+func (m *letterMemory) Remember(letters []rune) {
+	recent := slices.DeleteFunc([]rune(m.Recent), func(r rune) bool { return slices.Contains(letters, r) })
+	recent = append(recent, letters...)
+	m.Recent = string(recent[max(len(recent)-MEMORY_SIZE, 0):])
+}
+
+// Save writes the memory back to its file, atomically.
+func (m *letterMemory) Save() error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(m.path, append(data, '\n'))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadLetterMemory(t *testing.T) {
+	dir := t.TempDir()
+	for _, test := range []struct {
+		name, contents string
+		write          bool
+		recent         string
+	}{
+		{"missing", "", false, ""},
+		{"saved", `{"recent":"QXZ"}`, true, "QXZ"},
+		{"corrupt", `{"recent":`, true, ""},
+		{"wrong type", `{"recent":3}`, true, ""},
+	} {
+		path := filepath.Join(dir, test.name+".json")
+		if test.write {
+			if err := os.WriteFile(path, []byte(test.contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if m := loadLetterMemory(path); m.Recent != test.recent || m.path != path {
+			t.Errorf("%s: loaded %q from %s, want %q from %s", test.name, m.Recent, m.path, test.recent, path)
+		}
+	}
+}
+
+func TestRememberLetters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	m := loadLetterMemory(path)
+	m.Remember([]rune("AB"))
+	m.Remember([]rune("CA"))
+	if m.Recent != "BCA" {
+		t.Errorf("remembered %q, want BCA with A moved to the end", m.Recent)
+	}
+	m.Remember([]rune("DEFGHIJKLM"))
+	if len(m.Recent) != MEMORY_SIZE || m.Recent != "DEFGHIJKLM" {
+		t.Errorf("remembered %q, want only the last %d letters", m.Recent, MEMORY_SIZE)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if saved := loadLetterMemory(path); saved.Recent != m.Recent {
+		t.Errorf("saved %q, loaded %q", m.Recent, saved.Recent)
+	}
+}
+
+// playRemembering plays rounds unattended rounds with the given seed,
+// remembering their letters at path, first avoiding the ones already there
+// if avoid is set, and returns the letters drawn.
+func playRemembering(path string, seed int64, rounds int, avoid bool) []rune {
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("ABCDEFGHIJ"), Resolution: time.Second, Rounds: rounds}, TEST_PROMPTS, seed, nil, io.Discard)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.Memory = loadLetterMemory(path)
+	if avoid {
+		g.Avoid([]rune(g.Memory.Recent))
+	}
+	g.Run(&interrupter{out: io.Discard})
+	recent := []rune(g.Memory.Recent)
+	return recent[len(recent)-rounds:]
+}
+
+func TestMemoryAvoidsLastRunsLetters(t *testing.T) {
+	// Two evenings of five rounds each over ten letters: remembering, the
+	// second never repeats the first; without, it usually does
+	remembered, forgetful := 0, 0
+	for seed := int64(1); seed <= 20; seed++ {
+		path := filepath.Join(t.TempDir(), "memory.json")
+		first := playRemembering(path, seed, 5, true)
+		for _, letter := range playRemembering(path, seed+100, 5, true) {
+			if slices.Contains(first, letter) {
+				remembered++
+			}
+		}
+		path = filepath.Join(t.TempDir(), "memory.json")
+		first = playRemembering(path, seed, 5, false)
+		for _, letter := range playRemembering(path, seed+100, 5, false) {
+			if slices.Contains(first, letter) {
+				forgetful++
+			}
+		}
+	}
+	if remembered != 0 {
+		t.Errorf("repeated %d of the last run's letters with memory, want none", remembered)
+	}
+	if forgetful == 0 {
+		t.Error("never repeated the last run's letters without memory, so the test proves nothing")
+	}
+}
+
+func TestChosenSeedIgnoresMemory(t *testing.T) {
+	args := []string{"-letters", "ABCDEFGHIJKL", "-prompts", "2", "-duration", "1s", "-no-wait"}
+	saved := `{"recent":"ABCDEFGHIJ"}` + "\n"
+	remembering := func() (home, memory string) {
+		home = t.TempDir()
+		memory = filepath.Join(home, ".scattergories-letters.json")
+		if err := os.WriteFile(memory, []byte(saved), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return home, memory
+	}
+	// firstLetter finds the first round's letter in a game's or -plan's output
+	firstLetter := func(out string) string {
+		for _, before := range []string{"Letter: ", "Round 1: "} {
+			if _, after, ok := strings.Cut(out, before); ok {
+				return after[:1]
+			}
+		}
+		t.Fatalf("no letter drawn:\n%s", out)
+		return ""
+	}
+
+	// The same seed plans, and plays, the same rounds, memory or not
+	for _, run := range [][]string{{"-plan", "3"}, {"-rounds", "1"}} {
+		run = append(append(run, "-seed", "7"), args...)
+		forgetful, _ := runGame(t, "", run...)
+		if strings.ContainsAny(firstLetter(forgetful), "KL") {
+			t.Fatalf("seed 7 opens with a letter the memory doesn't hold back, so the test proves nothing:\n%s", forgetful)
+		}
+		home, memory := remembering()
+		if out, code := runGameIn(t, home, "", run...); code != 0 || firstLetter(out) != firstLetter(forgetful) {
+			t.Errorf("%q with a memory exited %d opening with %s, want %s as without:\n%s", run, code, firstLetter(out), firstLetter(forgetful), out)
+		}
+		if data, _ := os.ReadFile(memory); string(data) != saved {
+			t.Errorf("%q recorded its letters in the memory: %s", run, data)
+		}
+	}
+
+	// Without one the memory still holds back the letters it has
+	home, memory := remembering()
+	out, code := runGameIn(t, home, "", append([]string{"-rounds", "1"}, args...)...)
+	if letter := firstLetter(out); code != 0 || !strings.ContainsAny(letter, "KL") {
+		t.Errorf("exited %d opening with %s, want K or L:\n%s", code, letter, out)
+	}
+	if m := loadLetterMemory(memory); !strings.HasSuffix(m.Recent, firstLetter(out)) {
+		t.Errorf("memory is %q, want it to end with the letter played", m.Recent)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Menu shows the main menu and handles choices read from lines until one
+// starts a round, reporting whether it's a replay of the last one. Entering
+// nothing starts a round too, as does a start request from the -serve API or
+// StartTimeout passing without a choice. PAUSE_KEY pauses or resumes the
+// game, StartTimeout included, without otherwise counting as a choice.
+// It reports false if the players quit or input ran out.
+func (g *Game) Menu() (replay, ok bool) {
+	var timeout <-chan time.Time // nil, so never fires, without StartTimeout
+	for {
+		fmt.Fprintln(g.out, msg("menu"))
+		fmt.Fprintln(g.out, "  1.\t"+msg("menu-start"))
+		fmt.Fprintln(g.out, "  2.\t"+msg("menu-replay"))
+		fmt.Fprintln(g.out, "  3.\t"+msg("menu-settings"))
+		fmt.Fprintln(g.out, "  4.\t"+msg("menu-standings"))
+		fmt.Fprintln(g.out, "  5.\t"+msg("menu-quit"))
+		fmt.Fprintf(g.out, msg("menu-choose"), RECALL_KEY)
+		var line string
+		if g.StartTimeout > 0 && timeout == nil {
+			timeout = g.Clock.After(g.StartTimeout)
+		}
+		select {
+		case line, ok = <-g.lines:
+			if !ok {
+				fmt.Fprintln(g.out)
+				return false, false
+			}
+		case <-g.starts:
+			fmt.Fprintln(g.out)
+			return false, true
+		case <-timeout:
+			fmt.Fprintln(g.out)
+			fmt.Fprintln(g.out, msg("start-timeout"))
+			return false, true
+		}
+		choice := strings.TrimSpace(line)
+		if choice == PAUSE_KEY {
+			// Keep the same timeout, to carry on where it left off
+			if togglePause(g.Clock) {
+				fmt.Fprintf(g.out, msg("paused")+"\n", PAUSE_KEY)
+			} else {
+				fmt.Fprintln(g.out, msg("resumed"))
+			}
+			continue
+		}
+		timeout = nil
+		switch choice {
+		case "", "1":
+			return false, true
+		case "2", REPLAY_KEY:
+			if g.last == nil {
+				fmt.Fprintln(g.out, msg("no-replay"))
+				continue
+			}
+			return true, true
+		case "3":
+			if !g.Settings() {
+				return false, false
+			}
+		case "4":
+			if len(g.Board.Totals) == 0 {
+				fmt.Fprintln(g.out, msg("no-scores"))
+			} else {
+				g.Board.Print(g.out)
+			}
+		case RECALL_KEY:
+			if g.recall == nil {
+				fmt.Fprintln(g.out, msg("no-recall"))
+			} else {
+				g.Recall()
+			}
+		case "5", QUIT_KEY:
+			return false, false
+		default:
+			fmt.Fprintf(g.out, msg("not-on-menu")+"\n", choice)
+		}
+	}
+}
+
+// Recall shows the last round played again, with everyone's scores if it
+// was scored, without drawing anything.
+func (g *Game) Recall() {
+	round := *g.recall
+	printSummary(g.out, round, PLAYERS)
+	if len(round.Answers) == 0 {
+		return
+	}
+	totals := map[string]int{}
+	for _, answer := range round.Answers {
+		totals[answer.Player] += answer.Points
+	}
+	printScores(g.out, round, PLAYERS, totals)
+}
+
+// Settings lets the host change the round length, the number of prompts and
+// the letters between rounds. It reports false if input ran out.
+func (g *Game) Settings() bool {
+	for {
+		fmt.Fprintln(g.out, msg("settings"))
+		fmt.Fprintf(g.out, "  1.\t"+msg("set-length")+"\n", formatClock(g.Duration))
+		fmt.Fprintf(g.out, "  2.\t"+msg("set-prompts")+"\n", g.promptCountText())
+		fmt.Fprintf(g.out, "  3.\t"+msg("set-letters")+"\n", string(g.Letters))
+		fmt.Fprintln(g.out, "  4.\t"+msg("set-back"))
+		choice, ok := g.ask(msg("set-choose"))
+		if !ok {
+			return false
+		}
+		var err error
+		switch choice {
+		case "1":
+			value, ok := g.ask(msg("new-length"))
+			if !ok {
+				return false
+			}
+			err = g.setDuration(value)
+		case "2":
+			value, ok := g.ask(fmt.Sprintf(msg("new-prompts"), len(g.prompts)))
+			if !ok {
+				return false
+			}
+			err = g.setNumPrompts(value)
+		case "3":
+			value, ok := g.ask(msg("new-letters"))
+			if !ok {
+				return false
+			}
+			err = g.setLetters(value)
+		case "4", "":
+			return true
+		default:
+			err = fmt.Errorf(msg("not-a-setting"), choice)
+		}
+		if err != nil {
+			fmt.Fprintln(g.out, err)
+		}
+	}
+}
+
+// ask prints question and returns the next line of input, trimmed.
+func (g *Game) ask(question string) (string, bool) {
+	fmt.Fprint(g.out, question)
+	line, ok := <-g.lines
+	if !ok {
+		fmt.Fprintln(g.out)
+	}
+	return strings.TrimSpace(line), ok
+}
+
+func (g *Game) setDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fmt.Errorf(msg("bad-length"), value)
+	}
+	g.Duration = d
+	return nil
+}
+
+func (g *Game) setNumPrompts(value string) error {
+	lo, hi, err := parsePromptCount(value)
+	if err != nil {
+		return err
+	}
+	if hi > len(g.prompts) {
+		return fmt.Errorf(msg("prompts-range"), len(g.prompts))
+	}
+	g.NumPrompts, g.MaxPrompts = lo, hi
+	return nil
+}
+
+// setLetters switches to a new set of letters, starting a fresh cycle of
+// them.
+func (g *Game) setLetters(value string) error {
+	letters, err := buildLetters(value, "")
+	if err != nil {
+		return err
+	}
+	if len(letters) < g.LettersPer {
+		return fmt.Errorf(msg("letters-short"), g.LettersPer)
+	}
+	g.Letters = letters
+	g.letterPool = shuffled(g.rng, g.Letters)
+	return nil
+}
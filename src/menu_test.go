@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedGame makes a test game that reads script as its input.
+func scriptedGame(config Config, script string) (*Game, *bytes.Buffer) {
+	var out bytes.Buffer
+	return NewGame(config, TEST_PROMPTS, 1, readLines(strings.NewReader(script)), &out), &out
+}
+
+func TestMenu(t *testing.T) {
+	for _, test := range []struct {
+		name, script string
+		replay, ok   bool
+		says         []string
+	}{
+		{"enter starts", "\n", false, true, nil},
+		{"1 starts", "1\n", false, true, nil},
+		{"not on the menu", "9\n1\n", false, true, []string{fmt.Sprintf(msg("not-on-menu"), "9")}},
+		{"nothing to replay", "2\n5\n", false, false, []string{msg("no-replay")}},
+		{"no standings", "4\n" + QUIT_KEY + "\n", false, false, []string{msg("no-scores")}},
+		{"input runs out", "", false, false, nil},
+	} {
+		g, out := scriptedGame(Config{NumPrompts: 2, Letters: []rune("AB")}, test.script)
+		replay, ok := g.Menu()
+		if replay != test.replay || ok != test.ok {
+			t.Errorf("%s: Menu = %v, %v; want %v, %v", test.name, replay, ok, test.replay, test.ok)
+		}
+		for _, want := range test.says {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s: menu didn't say %q:\n%s", test.name, want, out.String())
+			}
+		}
+		// The menu is shown again after each choice it stays on
+		if n, want := strings.Count(out.String(), msg("menu")), len(test.says)+1; n != want {
+			t.Errorf("%s: showed the menu %d times, want %d", test.name, n, want)
+		}
+	}
+}
+
+func TestMenuReplay(t *testing.T) {
+	g, _ := scriptedGame(Config{NumPrompts: 2, Letters: []rune("AB")}, "2\n")
+	g.NextRound()
+	if replay, ok := g.Menu(); !replay || !ok {
+		t.Errorf("Menu = %v, %v after a round; want a replay", replay, ok)
+	}
+}
+
+func TestMenuSettings(t *testing.T) {
+	script := strings.Join([]string{
+		"3",         // settings
+		"1", "soon", // a bad length
+		"1", "90s", // round length
+		"2", "5", // prompts
+		"2", "50", // too many
+		"3", "abc", // letters
+		"3", "a1", // not letters
+		"4", // back to the menu
+		"1", // start
+	}, "\n") + "\n"
+	g, out := scriptedGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("XYZ")}, script)
+	if _, ok := g.Menu(); !ok {
+		t.Fatalf("input ran out:\n%s", out.String())
+	}
+	if g.Duration != 90*time.Second || g.NumPrompts != 5 || string(g.Letters) != "ABC" {
+		t.Errorf("settings are %s, %d prompts and %q; want 1m30s, 5 and ABC", g.Duration, g.NumPrompts, string(g.Letters))
+	}
+	if !strings.Contains(out.String(), fmt.Sprintf(msg("bad-length"), "soon")) {
+		t.Errorf("didn't reject the bad length:\n%s", out.String())
+	}
+	if n := strings.Count(out.String(), msg("settings")); n != 7 {
+		t.Errorf("showed the settings %d times, want 7", n)
+	}
+	round := g.NextRound()
+	if len(round.Prompts) != 5 || len(round.Letters) != 1 || !slices.Contains([]rune("ABC"), round.Letters[0]) {
+		t.Errorf("next round drew %q and %d prompts, want one of ABC and 5", string(round.Letters), len(round.Prompts))
+	}
+}
+
+func TestMenuStartTimeout(t *testing.T) {
+	lines := make(chan string)
+	var out bytes.Buffer
+	g := NewGame(Config{NumPrompts: 2, Letters: []rune("AB"), StartTimeout: time.Minute}, TEST_PROMPTS, 1, lines, &out)
+	clock := newFakeClock()
+	g.Clock = clock
+	type menuResult struct{ replay, ok bool }
+	done := make(chan menuResult, 1)
+	go func() {
+		replay, ok := g.Menu()
+		done <- menuResult{replay, ok}
+	}()
+
+	// Someone checks the standings 40s in, which starts the wait over
+	clock.BlockUntil(t, 1)
+	clock.Advance(40 * time.Second)
+	lines <- "4"
+	clock.BlockUntil(t, 2)
+	clock.Advance(40 * time.Second)
+	select {
+	case got := <-done:
+		t.Fatalf("Menu = %v, 80s in, 40s after a choice; want it still waiting", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(20 * time.Second)
+	if got := <-done; got.replay || !got.ok {
+		t.Errorf("Menu = %+v after timing out, want to start a round", got)
+	}
+	if !strings.Contains(out.String(), msg("no-scores")) || !strings.HasSuffix(out.String(), msg("start-timeout")+"\n") {
+		t.Errorf("didn't show the standings and then time out:\n%s", out.String())
+	}
+}
+
+func TestMenuChoiceBeatsStartTimeout(t *testing.T) {
+	lines := make(chan string, 1)
+	g := NewGame(Config{NumPrompts: 2, Letters: []rune("AB"), StartTimeout: time.Minute}, TEST_PROMPTS, 1, lines, io.Discard)
+	clock := newFakeClock()
+	g.Clock = clock
+	lines <- "5"
+	if _, ok := g.Menu(); ok {
+		t.Error("started a round, want the quit typed before the timeout to win")
+	}
+	// The timeout that didn't fire is forgotten, not left to start the next
+	// menu early
+	done := make(chan bool, 1)
+	go func() {
+		_, ok := g.Menu()
+		done <- ok
+	}()
+	clock.BlockUntil(t, 2)
+	clock.Advance(59 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("the second menu timed out early")
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	if !<-done {
+		t.Error("the second menu didn't time out")
+	}
+}
+
+func TestRecall(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	set(t, &PLAYERS, []string{"Al", "Bo"})
+	// Recall before any round, play one, recall it, then quit
+	script := RECALL_KEY + "\n1\n" + SKIP_KEY + "\nAnt\nArk\nAnt\nAxe\n" + RECALL_KEY + "\n5\n"
+	g, out := scriptedGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("A"), Resolution: time.Second}, script)
+	g.Clock = newFakeClock()
+	g.Run(&interrupter{out: out})
+	if g.Played != 1 {
+		t.Errorf("played %d rounds, want 1", g.Played)
+	}
+
+	menus := strings.Split(out.String(), msg("menu"))
+	if len(menus) != 5 {
+		t.Fatalf("showed the menu %d times, want 4:\n%s", len(menus)-1, out.String())
+	}
+	if !strings.Contains(menus[1], msg("no-recall")) {
+		t.Errorf("recalling before any round didn't say there's nothing to show:\n%s", menus[1])
+	}
+	recalled := menus[3]
+	for _, want := range []string{
+		fmt.Sprintf(msg("summary"), 1),
+		fmt.Sprintf(msg("summary-letter"), "A"),
+		"1.\t" + g.recall.Prompts[0], "2.\t" + g.recall.Prompts[1],
+		fmt.Sprintf(msg("scores"), 1),
+		"  Al\t1\n", "    2. Ark: 1\n",
+		"  Bo\t1\n", "    1. Ant: 0 (" + msg("duplicate") + ")\n",
+	} {
+		if !strings.Contains(recalled, want) {
+			t.Errorf("recall is missing %q:\n%s", want, recalled)
+		}
+	}
+	if g.Played != 1 || g.Board.Totals["Al"] != 1 {
+		t.Errorf("recalling changed the game: %d played, standings %v", g.Played, g.Board.Totals)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	HOST_WRITE_TIMEOUT = time.Second            // how long a client gets to take each message
+	HOST_POLL          = 100 * time.Millisecond // how often the host checks the game for changes
+)
+
+// netMessage is one line of the -host protocol. The host sends "round" when a
+// round's timer starts (or when a client joins mid-round), "tick" when the
+// remaining time or pause state changes, and "end" when the timer stops.
+type netMessage struct {
+	Type string `json:"type"`
+	roundState
+}
+
+// host broadcasts a game's rounds to TCP clients as newline-delimited JSON.
+type host struct {
+	ln      net.Listener
+	game    *Game
+	mu      sync.Mutex
+	clients map[net.Conn]*json.Encoder
+}
+
+func listenHost(addr string, g *Game) (*host, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	h := &host{ln: ln, game: g, clients: map[net.Conn]*json.Encoder{}}
+	go h.accept()
+	go h.follow()
+	return h, nil
+}
+
+// accept adds clients until the listener is closed, catching each one up on
+// the round in progress.
+func (h *host) accept() {
+	for {
+		conn, err := h.ln.Accept()
+		if err != nil {
+			return
+		}
+		h.mu.Lock()
+		h.clients[conn] = json.NewEncoder(conn)
+		if state := h.game.State(); state.Active {
+			h.send(conn, netMessage{"round", state})
+		}
+		h.mu.Unlock()
+		slog.Info("client joined", "addr", conn.RemoteAddr())
+	}
+}
+
+// follow polls the game every HOST_POLL and broadcasts what changed.
+func (h *host) follow() {
+	last := roundState{}
+	for range time.Tick(HOST_POLL) {
+		state := h.game.State()
+		switch {
+		case state.Active && (!last.Active || state.Round != last.Round || state.Letter != last.Letter):
+			h.broadcast(netMessage{"round", state})
+		case state.Active && (state.Remaining != last.Remaining || state.Paused != last.Paused):
+			h.broadcast(netMessage{"tick", state})
+		case !state.Active && last.Active:
+			h.broadcast(netMessage{"end", state})
+		}
+		last = state
+	}
+}
+
+func (h *host) broadcast(msg netMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.clients {
+		h.send(conn, msg)
+	}
+}
+
+// send writes msg to conn, dropping the client if that fails. Callers hold mu.
+func (h *host) send(conn net.Conn, msg netMessage) {
+	conn.SetWriteDeadline(time.Now().Add(HOST_WRITE_TIMEOUT))
+	if err := h.clients[conn].Encode(msg); err != nil {
+		slog.Info("client left", "addr", conn.RemoteAddr(), "err", err)
+		conn.Close()
+		delete(h.clients, conn)
+	}
+}
+
+// Close stops accepting clients and disconnects the ones there are.
+func (h *host) Close() error {
+	err := h.ln.Close()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.clients {
+		conn.Close()
+		delete(h.clients, conn)
+	}
+	return err
+}
+
+// joinGame connects to a -host and shows its rounds on w until the host goes
+// away.
+func joinGame(addr string, w io.Writer) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(w, msg("connected")+"\n", addr)
+
+	var view timerView
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var message netMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			slog.Warn("bad message from host", "err", err)
+			continue
+		}
+		switch message.Type {
+		case "round":
+			if view != nil {
+				view.Stop(w)
+			}
+			view = showRound(w, message.roundState)
+			fallthrough
+		case "tick":
+			if view == nil {
+				continue
+			}
+			remaining := time.Duration(message.Remaining) * time.Second
+			total := time.Duration(message.Total) * time.Second
+			bar := ""
+			if BAR_WIDTH > 0 {
+				bar = " " + progressBar(total-remaining, total, BAR_WIDTH)
+			}
+			label := timerLabel(total-remaining, remaining, timerShowing())
+			view.Tick(w, paint(timerColor(remaining, total), label), bar, message.Paused)
+		case "end":
+			if view == nil {
+				continue
+			}
+			view.Stop(w)
+			view = nil
+			fmt.Fprintln(w, msg("round-over"))
+			fmt.Fprintln(w, SEP)
+		}
+	}
+	if view != nil {
+		view.Stop(w)
+	}
+	fmt.Fprintln(w, msg("host-left"))
+	return scanner.Err()
+}
+
+// showRound prints a round received from the host the way Play does and
+// returns the view to draw its timer on.
+func showRound(w io.Writer, state roundState) timerView {
+	round := &Round{Number: state.Round, Prompts: state.Prompts}
+	fmt.Fprintln(w, SEP)
+	fmt.Fprintf(w, msg("round")+"\n", round.Number)
+	if state.Letter != "" {
+		fmt.Fprintf(w, LETTER_FORMAT+"\n", painted{COLOR_LETTER, state.Letter})
+	}
+	fmt.Fprintln(w, msg("prompts"))
+	for i, prompt := range round.Prompts {
+		fmt.Fprintf(w, PROMPT_FORMAT+"\n", painted{COLOR_DIM, i + 1}, prompt)
+	}
+	fmt.Fprintln(w, "")
+	var view timerView = &lineView{}
+	if QUIET {
+		view = quietView{}
+	} else if TUI {
+		view = &tuiView{round: round, shown: len(round.Prompts)}
+	}
+	view.Start(w)
+	return view
+}
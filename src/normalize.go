@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Normalization picks what normalizeAnswer ignores when comparing answers,
+// set with -normalize. Leading and trailing space is always ignored.
+type Normalization struct {
+	Case    bool // "New York" matches "new york"
+	Space   bool // "new  york" matches "new york"
+	NoSpace bool // "hot dog" matches "hotdog"
+	Punct   bool // "O'Hare" matches "OHare"
+	Accents bool // "café" matches "cafe"
+}
+
+// NORMALIZATIONS are the steps -normalize can list, in the order
+// Normalization's fields give them.
+var NORMALIZATIONS = []string{"case", "space", "nospace", "punct", "accents"}
+
+// parseNormalization reads a -normalize list such as "case,space,accents".
+// "none" or an empty list compares answers exactly, bar the ends' spaces.
+func parseNormalization(s string) (Normalization, error) {
+	n := Normalization{}
+	for _, step := range splitList(strings.ToLower(s)) {
+		switch step {
+		case "none":
+		case "case":
+			n.Case = true
+		case "space":
+			n.Space = true
+		case "nospace":
+			n.NoSpace = true
+		case "punct":
+			n.Punct = true
+		case "accents":
+			n.Accents = true
+		default:
+			return n, fmt.Errorf("%q isn't none or %s", step, strings.Join(NORMALIZATIONS, ", "))
+		}
+	}
+	return n, nil
+}
+
+func (n Normalization) String() string {
+	steps := []string{}
+	for i, on := range []bool{n.Case, n.Space, n.NoSpace, n.Punct, n.Accents} {
+		if on {
+			steps = append(steps, NORMALIZATIONS[i])
+		}
+	}
+	if len(steps) == 0 {
+		return "none"
+	}
+	return strings.Join(steps, ",")
+}
+
+// Apply reduces text to the form n compares it in.
+func (n Normalization) Apply(text string) string {
+	if n.Accents {
+		text = foldAccents(text)
+	}
+	if n.Punct {
+		text = strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return -1
+			}
+			return r
+		}, text)
+	}
+	if n.Case {
+		text = strings.ToLower(text)
+	}
+	switch {
+	case n.NoSpace:
+		return strings.Join(strings.Fields(text), "")
+	case n.Space:
+		return strings.Join(strings.Fields(text), " ")
+	}
+	return strings.TrimSpace(text)
+}
+
+// ACCENTS maps each unaccented letter to the accented forms foldAccents turns
+// into it. Their capitals fold to its capital.
+var ACCENTS = map[string]string{
+	"a":  "àáâãäåāăą",
+	"c":  "çćĉċč",
+	"d":  "ďđ",
+	"e":  "èéêëēĕėęě",
+	"g":  "ĝğġģ",
+	"h":  "ĥħ",
+	"i":  "ìíîïĩīĭįı",
+	"j":  "ĵ",
+	"k":  "ķ",
+	"l":  "ĺļľŀł",
+	"n":  "ñńņňŉ",
+	"o":  "òóôõöøōŏő",
+	"r":  "ŕŗř",
+	"s":  "śŝşš",
+	"t":  "ţťŧ",
+	"u":  "ùúûüũūŭůűų",
+	"w":  "ŵ",
+	"y":  "ýÿŷ",
+	"z":  "źżž",
+	"ae": "æ",
+	"oe": "œ",
+	"ss": "ß",
+}
+
+var accentFolder = newAccentFolder()
+
+func newAccentFolder() *strings.Replacer {
+	pairs := []string{}
+	for plain, accented := range ACCENTS {
+		for _, r := range accented {
+			pairs = append(pairs, string(r), plain)
+			if upper := unicode.ToUpper(r); upper != r {
+				pairs = append(pairs, string(upper), strings.ToUpper(plain[:1])+plain[1:])
+			}
+		}
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// foldAccents strips the accents from the Latin letters in text, whether
+// they're written as one character or a letter followed by combining marks.
+func foldAccents(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, text)
+	return accentFolder.Replace(text)
+}
@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+func TestNormalizationApply(t *testing.T) {
+	all := Normalization{Case: true, Space: true, Punct: true, Accents: true}
+	for _, test := range []struct {
+		n          Normalization
+		text, want string
+	}{
+		{Normalization{}, "  New  York ", "New  York"},
+		{Normalization{Case: true}, "New York", "new york"},
+		{Normalization{Space: true}, "New \t York  City", "New York City"},
+		{Normalization{NoSpace: true}, " hot  dog ", "hotdog"},
+		{Normalization{Space: true, NoSpace: true}, "hot dog", "hotdog"},
+		{Normalization{Punct: true}, "O'Hare, St. Louis!", "OHare St Louis"},
+		{Normalization{Punct: true}, "Jell-O", "JellO"},
+		{Normalization{Accents: true}, "Café Crème", "Cafe Creme"},
+		{Normalization{Accents: true}, "Cafe\u0301", "Cafe"}, // a combining accent
+		{Normalization{Accents: true}, "ÆSOP Œuvre straße", "AeSOP Oeuvre strasse"},
+		{Normalization{Accents: true}, "Zürich, Kraków, São Paulo", "Zurich, Krakow, Sao Paulo"},
+		{Normalization{Accents: true}, "東京", "東京"},
+		{all, "  Crème   Brûlée! ", "creme brulee"},
+		{all, "crème-brûlée", "cremebrulee"},
+		{all, "St. Étienne", "st etienne"},
+	} {
+		if got := test.n.Apply(test.text); got != test.want {
+			t.Errorf("%s: Apply(%q) = %q, want %q", test.n, test.text, got, test.want)
+		}
+	}
+}
+
+func TestParseNormalization(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"", "none"},
+		{"none", "none"},
+		{"case,space", "case,space"},
+		{" Accents , CASE ", "case,accents"},
+		{"punct,nospace,case", "case,nospace,punct"},
+	} {
+		n, err := parseNormalization(test.in)
+		if err != nil || n.String() != test.want {
+			t.Errorf("parseNormalization(%q) = %s, %v; want %s", test.in, n, err, test.want)
+		}
+	}
+	if _, err := parseNormalization("case,soundex"); err == nil {
+		t.Error("parsed an unknown step")
+	}
+	if NORMALIZE.String() != "case,space" {
+		t.Errorf("default normalization is %s, want case,space", NORMALIZE)
+	}
+}
+
+func TestDuplicatesUseNormalization(t *testing.T) {
+	answers := map[string][]string{"Al": {"Crème  Brûlée"}, "Bo": {"creme brulee"}, "Cy": {"Crème-Brûlée!"}}
+	for _, test := range []struct {
+		normalize string
+		totals    map[string]int
+	}{
+		{"none", map[string]int{"Al": 1, "Bo": 1, "Cy": 1}},
+		{"case,space", map[string]int{"Al": 1, "Bo": 1, "Cy": 1}},
+		{"case,space,accents", map[string]int{"Al": 0, "Bo": 0, "Cy": 1}},
+		{"case,nospace,punct,accents", map[string]int{"Al": 0, "Bo": 0, "Cy": 0}},
+	} {
+		n, err := parseNormalization(test.normalize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set(t, &NORMALIZE, n)
+		round := roundWith("C", answers)
+		totals := scoreRound(&round, ScoringRules{Unique: 1})
+		for _, player := range sortedNames(answers) {
+			if totals[player] != test.totals[player] {
+				t.Errorf("%s: %s scored %d, want %d", test.normalize, player, totals[player], test.totals[player])
+			}
+		}
+	}
+}
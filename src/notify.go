@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// Notifier shows a desktop notification, so players who've tabbed away see
+// when a round ends.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// commandNotifier notifies by running an OS tool: osascript on macOS and
+// notify-send elsewhere.
+type commandNotifier struct {
+	tool string // path to the tool
+}
+
+// newNotifier returns a Notifier for this OS, or nil if its tool isn't
+// installed.
+func newNotifier() Notifier {
+	name := "notify-send"
+	if runtime.GOOS == "darwin" {
+		name = "osascript"
+	}
+	tool, err := exec.LookPath(name)
+	if err != nil {
+		slog.Debug("desktop notifications unavailable", "tool", name, "err", err)
+		return nil
+	}
+	return commandNotifier{tool}
+}
+
+func (n commandNotifier) Notify(title, message string) error {
+	cmd := exec.Command(n.tool, title, message)
+	if runtime.GOOS == "darwin" {
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+		cmd = exec.Command(n.tool, "-e", script)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeNotifier records each notification on a channel, and fails them all
+// if err is set.
+type fakeNotifier struct {
+	sent chan string
+	err  error
+}
+
+func (n fakeNotifier) Notify(title, message string) error {
+	n.sent <- title + ": " + message
+	return n.err
+}
+
+// waitForNotifications returns the next n notifications sent to notifier.
+func waitForNotifications(t *testing.T, notifier fakeNotifier, n int) []string {
+	t.Helper()
+	sent := []string{}
+	for len(sent) < n {
+		select {
+		case message := <-notifier.sent:
+			sent = append(sent, message)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got notifications %q, want %d", sent, n)
+		}
+	}
+	return sent
+}
+
+func TestNotifyAtRoundEnd(t *testing.T) {
+	notifier := fakeNotifier{sent: make(chan string, 10), err: errors.New("no display")}
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("AB"), Resolution: time.Second, Rounds: 2}, TEST_PROMPTS, 1, nil, io.Discard)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.Notifier = notifier
+	g.Run(&interrupter{out: io.Discard})
+	for _, message := range waitForNotifications(t, notifier, 2) {
+		if message != "Scattergories: "+msg("times-up") {
+			t.Errorf("notified %q, want time's up", message)
+		}
+	}
+}
+
+func TestNotifyEndedEarly(t *testing.T) {
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	set(t, &QUIET, true)
+	notifier := fakeNotifier{sent: make(chan string, 10)}
+	// Redraw the first draw, which isn't a round ending, then end the next early
+	script := "1\n" + REDRAW_KEY + "\n" + SKIP_KEY + "\n5\n"
+	g, _ := scriptedGame(Config{Duration: time.Minute, NumPrompts: 1, Letters: []rune("AB"), Resolution: time.Second}, script)
+	g.Clock = newFakeClock()
+	g.Notifier = notifier
+	g.Run(&interrupter{out: io.Discard})
+	if sent := waitForNotifications(t, notifier, 1); !strings.HasSuffix(sent[0], msg("ended-early")) {
+		t.Errorf("notified %q, want the round ended early", sent[0])
+	}
+	select {
+	case message := <-notifier.sent:
+		t.Errorf("also notified %q", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Pack is a named prompts file found in the packs directory.
+type Pack struct {
+	Name string
+	Path string
+}
+
+// findPacks lists the .txt files in dir as packs named after the file, sorted
+// by name.
+func findPacks(dir string) ([]Pack, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return []Pack{}, fmt.Errorf("reading packs directory: %w", err)
+	}
+	packs := []Pack{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".txt" {
+			continue
+		}
+		packs = append(packs, Pack{
+			Name: strings.TrimSuffix(entry.Name(), ".txt"),
+			Path: filepath.Join(dir, entry.Name()),
+		})
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	if len(packs) == 0 {
+		return packs, fmt.Errorf("no .txt packs found in %s", dir)
+	}
+	return packs, nil
+}
+
+// selectPacks picks packs by name (case-insensitive) or by their 1-based
+// position in packs.
+func selectPacks(packs []Pack, choices []string) ([]Pack, error) {
+	selected := []Pack{}
+	for _, choice := range choices {
+		pack, ok := Pack{}, false
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(packs) {
+			pack, ok = packs[n-1], true
+		}
+		for _, p := range packs {
+			if strings.EqualFold(p.Name, choice) {
+				pack, ok = p, true
+			}
+		}
+		if !ok {
+			return []Pack{}, fmt.Errorf("no pack named %q", choice)
+		}
+		selected = append(selected, pack)
+	}
+	if len(selected) == 0 {
+		return selected, fmt.Errorf("no packs chosen")
+	}
+	return selected, nil
+}
+
+// choosePacks lists packs and asks until a valid choice is read from lines.
+// It reports false if input ran out first.
+func choosePacks(packs []Pack, lines <-chan string, w io.Writer) ([]Pack, bool) {
+	fmt.Fprintln(w, "Prompt packs:")
+	for i, pack := range packs {
+		fmt.Fprintf(w, "  %d.\t%s\n", i+1, pack.Name)
+	}
+	for {
+		fmt.Fprint(w, "Choose one or more packs, comma-separated: ")
+		line, ok := <-lines
+		if !ok {
+			fmt.Fprintln(w)
+			return []Pack{}, false
+		}
+		selected, err := selectPacks(packs, splitList(line))
+		if err == nil {
+			return selected, true
+		}
+		fmt.Fprintln(w, err)
+	}
+}
+
+func packPaths(packs []Pack) []string {
+	paths := []string{}
+	for _, pack := range packs {
+		paths = append(paths, pack.Path)
+	}
+	return paths
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindPacks(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"sports.txt", "animals.txt", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("Prompt\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "old.txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	packs, err := findPacks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Pack{{"animals", filepath.Join(dir, "animals.txt")}, {"sports", filepath.Join(dir, "sports.txt")}}
+	if !slices.Equal(packs, want) {
+		t.Errorf("found %v, want %v", packs, want)
+	}
+
+	if _, err := findPacks(t.TempDir()); err == nil {
+		t.Error("an empty directory gave no error")
+	}
+	if _, err := findPacks(filepath.Join(dir, "missing")); err == nil {
+		t.Error("a missing directory gave no error")
+	}
+}
+
+func TestSelectPacks(t *testing.T) {
+	packs := []Pack{{"animals", "a.txt"}, {"sports", "s.txt"}, {"tv", "t.txt"}}
+	for _, test := range []struct {
+		choices []string
+		want    []string
+	}{
+		{[]string{"sports"}, []string{"s.txt"}},
+		{[]string{"TV", "1"}, []string{"t.txt", "a.txt"}},
+		{[]string{"3"}, []string{"t.txt"}},
+		{[]string{"movies"}, nil},
+		{[]string{"4"}, nil},
+		{[]string{}, nil},
+	} {
+		selected, err := selectPacks(packs, test.choices)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("choosing %q gave %v, want an error", test.choices, selected)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(packPaths(selected), test.want) {
+			t.Errorf("choosing %q gave %v, %v; want %q", test.choices, selected, err, test.want)
+		}
+	}
+}
+
+func TestChoosePacksAsksAgain(t *testing.T) {
+	packs := []Pack{{"animals", "a.txt"}, {"sports", "s.txt"}}
+	lines := make(chan string, 2)
+	lines <- "movies"
+	lines <- "2, animals"
+	close(lines)
+	selected, ok := choosePacks(packs, lines, io.Discard)
+	if !ok || !slices.Equal(packPaths(selected), []string{"s.txt", "a.txt"}) {
+		t.Errorf("chose %v, %v; want sports and animals", selected, ok)
+	}
+}
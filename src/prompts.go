@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Built-in prompts, used when the prompts file can't be opened.
+//
+//go:embed scattergories.txt
+var DEFAULT_PROMPTS string
+
+// Prompt is one entry from a prompts file, written as the prompt text
+// optionally followed by | and a comma-separated list of tags and a weight:
+//
+//	Animals|easy,nature
+//	Famous athletes|sports,3
+type Prompt struct {
+	Text   string
+	Tags   []string
+	Weight float64 // relative chance of being drawn; 1 unless given
+}
+
+// PromptSource is somewhere prompts can be loaded from: a file, the built-in
+// list, a URL.
+type PromptSource interface {
+	Prompts() ([]Prompt, error)
+}
+
+// fileSource loads prompts from a prompts file, falling back to the built-in
+// list if it can't be opened.
+type fileSource string
+
+func (path fileSource) Prompts() ([]Prompt, error) {
+	return readPromptsFile(string(path))
+}
+
+// embeddedSource loads the built-in prompts.
+type embeddedSource struct{}
+
+func (embeddedSource) Prompts() ([]Prompt, error) {
+	return readPrompts("built-in prompts", strings.NewReader(DEFAULT_PROMPTS))
+}
+
+// fileSources makes a fileSource of each of paths.
+func fileSources(paths []string) []PromptSource {
+	sources := []PromptSource{}
+	for _, path := range paths {
+		sources = append(sources, fileSource(path))
+	}
+	return sources
+}
+
+// getPrompts loads and combines the prompts from each of sources, keeping
+// only those matching TAGS if any are set.
+func getPrompts(sources ...PromptSource) ([]Prompt, error) {
+	prompts := []Prompt{}
+	for _, source := range sources {
+		more, err := source.Prompts()
+		if err != nil {
+			return []Prompt{}, err
+		}
+		prompts = append(prompts, more...)
+	}
+	if len(TAGS) > 0 {
+		prompts = filterByTags(prompts, TAGS)
+	}
+	prompts, removed := dedupPrompts(prompts, DEDUP_IGNORE_CASE)
+	if removed > 0 {
+		slog.Info("removed duplicate prompts", "count", removed)
+	}
+	return prompts, nil
+}
+
+// promptTexts returns just the text of each prompt.
+func promptTexts(prompts []Prompt) []string {
+	texts := []string{}
+	for _, prompt := range prompts {
+		texts = append(texts, prompt.Text)
+	}
+	return texts
+}
+
+// promptWeights maps the text of each prompt that doesn't weigh 1 to its
+// weight.
+func promptWeights(prompts []Prompt) map[string]float64 {
+	weights := map[string]float64{}
+	for _, prompt := range prompts {
+		if prompt.Weight != 1 {
+			weights[prompt.Text] = prompt.Weight
+		}
+	}
+	return weights
+}
+
+// promptWeight is prompt's weight, defaulting to 1 for unlisted prompts.
+func promptWeight(weights map[string]float64, prompt string) float64 {
+	if w, ok := weights[prompt]; ok {
+		return w
+	}
+	return 1
+}
+
+// weightedPrompts draws n different prompts, each with probability
+// proportional to its weight among those left. Prompts in avoid are only
+// drawn once the others run out.
+func weightedPrompts(rng *rand.Rand, prompts []string, weights map[string]float64, avoid []string, n int) []string {
+	drawn := []string{}
+	for len(drawn) < n {
+		candidates := []string{}
+		total := 0.0
+		for _, skipAvoided := range []bool{true, false} {
+			for _, prompt := range prompts {
+				if slices.Contains(drawn, prompt) || skipAvoided && slices.Contains(avoid, prompt) {
+					continue
+				}
+				candidates = append(candidates, prompt)
+				total += promptWeight(weights, prompt)
+			}
+			if len(candidates) > 0 {
+				break
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		pick := rng.Float64() * total
+		chosen := candidates[len(candidates)-1]
+		for _, prompt := range candidates {
+			if pick -= promptWeight(weights, prompt); pick < 0 {
+				chosen = prompt
+				break
+			}
+		}
+		drawn = append(drawn, chosen)
+	}
+	return drawn
+}
+
+// How many times, and how far apart, opening a prompts file is retried while
+// it's locked. The wait doubles with each retry.
+var (
+	OPEN_RETRIES = 3
+	OPEN_BACKOFF = 100 * time.Millisecond
+)
+
+// openPromptsFile opens prompts files; tests can swap it out.
+var openPromptsFile = func(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+// openRetrying opens path with open, retrying up to OPEN_RETRIES times if
+// the error is one that should clear by itself, such as the file being
+// locked by an editor on Windows. Other errors are returned straight away.
+func openRetrying(open func(string) (io.ReadCloser, error), path string) (io.ReadCloser, error) {
+	wait := OPEN_BACKOFF
+	for retry := 0; ; retry++ {
+		file, err := open(path)
+		if err == nil || !isTransient(err) {
+			return file, err
+		}
+		if retry == OPEN_RETRIES {
+			return nil, fmt.Errorf("%s is locked, probably because it's open in another program; close it and try again: %w", path, err)
+		}
+		slog.Debug("prompts file locked, retrying", "path", path, "wait", wait, "err", err)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// isTransient reports whether err is from a file being briefly busy or
+// locked rather than missing or forbidden.
+func isTransient(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	switch errno {
+	case syscall.EAGAIN, syscall.EBUSY, syscall.EINTR:
+		return true
+	}
+	// ERROR_SHARING_VIOLATION and ERROR_LOCK_VIOLATION
+	return runtime.GOOS == "windows" && (errno == 32 || errno == 33)
+}
+
+// readPromptsFile reads prompts from path, falling back to the built-in list
+// if it doesn't exist or can't be opened. It fails if the file stays locked.
+func readPromptsFile(path string) ([]Prompt, error) {
+	file, err := openRetrying(openPromptsFile, path)
+	if errors.Is(err, os.ErrNotExist) {
+		slog.Warn("prompts file not found, using built-in prompts", "path", path)
+		return embeddedSource{}.Prompts()
+	} else if isTransient(err) {
+		return []Prompt{}, err
+	} else if err != nil {
+		slog.Warn("can't open prompts file, using built-in prompts", "path", path, "err", err)
+		return embeddedSource{}.Prompts()
+	}
+	defer file.Close()
+	return readPrompts(path, file)
+}
+
+// readPrompts reads one prompt per line from r, named name in any error.
+// Surrounding whitespace is trimmed, and blank lines and lines starting with
+// # are skipped. It fails on a line with a bad weight.
+func readPrompts(name string, r io.Reader) ([]Prompt, error) {
+	prompts := []Prompt{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prompt, err := parsePrompt(line)
+		if err != nil {
+			return []Prompt{}, fmt.Errorf("%s:%d: %w", name, n, err)
+		}
+		prompts = append(prompts, prompt)
+	}
+	if err := scanner.Err(); err != nil {
+		return []Prompt{}, err
+	}
+	return prompts, nil
+}
+
+// parsePrompt splits a prompts file line into its text, lowercased tags and
+// weight. It fails if a tag looks like a weight but isn't a valid one.
+func parsePrompt(line string) (Prompt, error) {
+	text, tags, _ := strings.Cut(line, "|")
+	prompt := Prompt{Text: strings.TrimSpace(text), Tags: []string{}, Weight: 1}
+	for _, tag := range splitList(tags) {
+		w, isWeight, err := parseWeight(tag)
+		switch {
+		case err != nil:
+			return Prompt{}, err
+		case isWeight:
+			prompt.Weight = w
+		default:
+			prompt.Tags = append(prompt.Tags, strings.ToLower(tag))
+		}
+	}
+	return prompt, nil
+}
+
+// parseWeight reads tag as a weight if it's a number or starts like one,
+// reporting whether it is one. Weights must be finite and above zero.
+func parseWeight(tag string) (w float64, isWeight bool, err error) {
+	w, err = strconv.ParseFloat(tag, 64)
+	if err != nil && !looksLikeWeight(tag) {
+		return 0, false, nil
+	}
+	if err != nil || math.IsInf(w, 0) || math.IsNaN(w) || w <= 0 {
+		return 0, true, fmt.Errorf("bad weight %q: weights are finite numbers above 0", tag)
+	}
+	return w, true, nil
+}
+
+// filterByTags keeps the prompts carrying at least one of tags. Untagged
+// prompts never match.
+func filterByTags(prompts []Prompt, tags []string) []Prompt {
+	out := []Prompt{}
+	for _, prompt := range prompts {
+		for _, tag := range prompt.Tags {
+			if slices.Contains(tags, tag) {
+				out = append(out, prompt)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// dedupPrompts drops repeated prompts, keeping the first occurrence of each,
+// and returns the remaining prompts along with how many were dropped.
+func dedupPrompts(prompts []Prompt, ignoreCase bool) ([]Prompt, int) {
+	seen := map[string]bool{}
+	out := []Prompt{}
+	for _, prompt := range prompts {
+		key := prompt.Text
+		if ignoreCase {
+			key = strings.ToLower(key)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, prompt)
+	}
+	return out, len(prompts) - len(out)
+}
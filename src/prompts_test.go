@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestEmbeddedPromptsAreTheFallback(t *testing.T) {
+	embedded, err := readPrompts("built-in prompts", strings.NewReader(DEFAULT_PROMPTS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(embedded) == 0 {
+		t.Fatal("no built-in prompts")
+	}
+	for _, prompt := range embedded {
+		if prompt.Text == "" {
+			t.Fatalf("built-in prompts include a blank one: %+v", prompt)
+		}
+	}
+
+	missing, err := getPrompts(fileSource(filepath.Join(t.TempDir(), "missing.txt")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if builtIn, _ := dedupPrompts(embedded, DEDUP_IGNORE_CASE); !slices.Equal(promptTexts(missing), promptTexts(builtIn)) {
+		t.Error("a missing prompts file didn't fall back to the built-in prompts")
+	}
+
+	path := filepath.Join(t.TempDir(), "prompts.txt")
+	if err := os.WriteFile(path, []byte("Only this one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	own, err := getPrompts(fileSource(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if texts := promptTexts(own); !slices.Equal(texts, []string{"Only this one"}) {
+		t.Errorf("prompts file gave %q, want it to take precedence", texts)
+	}
+}
+
+func TestReadPromptsSkipsCommentsAndBlankLines(t *testing.T) {
+	file := "# Animals and such\n\nAnimals\n   \n  Things in a kitchen  \n\t# indented comment\nBirds\t\n"
+	prompts, err := readPrompts("test", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Animals", "Things in a kitchen", "Birds"}
+	if got := promptTexts(prompts); !slices.Equal(got, want) {
+		t.Errorf("read %q, want %q", got, want)
+	}
+}
+
+func TestDedupPrompts(t *testing.T) {
+	prompts, err := readPrompts("test", strings.NewReader("Animals\nBirds\n  Animals \nanimals\nCars\nBirds\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		ignoreCase bool
+		want       []string
+	}{
+		{false, []string{"Animals", "Birds", "animals", "Cars"}},
+		{true, []string{"Animals", "Birds", "Cars"}},
+	} {
+		kept, removed := dedupPrompts(prompts, test.ignoreCase)
+		if got := promptTexts(kept); !slices.Equal(got, test.want) {
+			t.Errorf("ignoring case %v kept %q, want %q", test.ignoreCase, got, test.want)
+		}
+		if want := len(prompts) - len(test.want); removed != want {
+			t.Errorf("ignoring case %v removed %d, want %d", test.ignoreCase, removed, want)
+		}
+	}
+}
+
+func TestParsePromptTags(t *testing.T) {
+	for _, test := range []struct {
+		line string
+		want Prompt
+	}{
+		{"Animals", Prompt{"Animals", []string{}, 1}},
+		{"Animals|easy", Prompt{"Animals", []string{"easy"}, 1}},
+		{" Famous athletes | Sports, HARD ,, ", Prompt{"Famous athletes", []string{"sports", "hard"}, 1}},
+		{"Famous athletes|sports,3", Prompt{"Famous athletes", []string{"sports"}, 3}},
+		{"Birds|", Prompt{"Birds", []string{}, 1}},
+	} {
+		got, err := parsePrompt(test.line)
+		if err != nil {
+			t.Errorf("parsePrompt(%q): %v", test.line, err)
+		} else if got.Text != test.want.Text || !slices.Equal(got.Tags, test.want.Tags) || got.Weight != test.want.Weight {
+			t.Errorf("parsePrompt(%q) = %+v, want %+v", test.line, got, test.want)
+		}
+	}
+}
+
+func TestFilterByTags(t *testing.T) {
+	file := "Animals|easy,nature\nRivers|nature\nTaxes\nOperas|hard\n"
+	prompts, err := readPrompts("test", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		tags []string
+		want []string
+	}{
+		{[]string{"easy"}, []string{"Animals"}},
+		{[]string{"nature"}, []string{"Animals", "Rivers"}},
+		{[]string{"easy", "hard"}, []string{"Animals", "Operas"}},
+		{[]string{"sports"}, []string{}},
+	} {
+		if got := promptTexts(filterByTags(prompts, test.tags)); !slices.Equal(got, test.want) {
+			t.Errorf("tags %q kept %q, want %q", test.tags, got, test.want)
+		}
+	}
+
+	set(t, &TAGS, []string{"nature"})
+	filtered, err := getPrompts(promptList(prompts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := promptTexts(filtered); !slices.Equal(got, []string{"Animals", "Rivers"}) {
+		t.Errorf("getPrompts with TAGS kept %q", got)
+	}
+}
+
+// promptList is a PromptSource of prompts already loaded.
+type promptList []Prompt
+
+func (l promptList) Prompts() ([]Prompt, error) { return l, nil }
+
+// brokenSource is a PromptSource that can't be loaded.
+type brokenSource struct{ err error }
+
+func (s brokenSource) Prompts() ([]Prompt, error) { return nil, s.err }
+
+func TestGetPromptsFromSources(t *testing.T) {
+	first := promptList{{Text: "Animals", Weight: 1}, {Text: "Rivers", Weight: 1}}
+	second := promptList{{Text: "rivers", Weight: 1}, {Text: "Operas", Weight: 2}}
+	set(t, &DEDUP_IGNORE_CASE, true)
+	prompts, err := getPrompts(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := promptTexts(prompts); !slices.Equal(got, []string{"Animals", "Rivers", "Operas"}) {
+		t.Errorf("combined sources gave %q, want each prompt once, in order", got)
+	}
+	if weights := promptWeights(prompts); weights["Operas"] != 2 {
+		t.Errorf("combined sources lost a weight: %v", weights)
+	}
+
+	g := newTestGame(Config{NumPrompts: 3, Letters: []rune("A")}, promptTexts(prompts))
+	if got := g.NextRound().Prompts; len(got) != 3 || slices.ContainsFunc(got, func(p string) bool { return !slices.Contains(promptTexts(prompts), p) }) {
+		t.Errorf("game drew %q, want only prompts from the sources", got)
+	}
+
+	broken := errors.New("no connection")
+	if _, err := getPrompts(first, brokenSource{broken}); !errors.Is(err, broken) {
+		t.Errorf("a failing source gave %v, want %v", err, broken)
+	}
+}
+
+func TestPromptWeights(t *testing.T) {
+	prompts, err := readPrompts("pack.txt", strings.NewReader("Animals|5\nBands|0.25, music\nCars\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := promptWeights(prompts)
+	if len(weights) != 2 || weights["Animals"] != 5 || weights["Bands"] != 0.25 {
+		t.Errorf("weights %v, want Animals 5 and Bands 0.25", weights)
+	}
+	if !slices.Equal(prompts[1].Tags, []string{"music"}) {
+		t.Errorf("Bands is tagged %q, want just music", prompts[1].Tags)
+	}
+}
+
+func TestBadPromptWeights(t *testing.T) {
+	for _, line := range []string{"Animals|0", "Animals|-2", "Animals|inf", "Animals|NaN", "Animals|1e999", "Animals|.5x"} {
+		_, err := readPrompts("pack.txt", strings.NewReader("Bands\n\n"+line+"\n"))
+		if err == nil || !strings.HasPrefix(err.Error(), "pack.txt:3: bad weight") {
+			t.Errorf("%q: readPrompts = %v, want a bad weight error at pack.txt:3", line, err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "prompts.txt")
+	if err := os.WriteFile(path, []byte("Animals|-1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fileSource(path).Prompts(); err == nil || !strings.HasPrefix(err.Error(), path+":1: ") {
+		t.Errorf("loading %s = %v, want an error naming its first line", path, err)
+	}
+}
+
+func TestWeightedPrompts(t *testing.T) {
+	const ROUNDS = 3000
+	prompts := TEST_PROMPTS[:6]
+	weights := map[string]float64{"Animals": 6, "Desserts": 0.2}
+	rng := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	var last []string
+	for i := 0; i < ROUNDS; i++ {
+		drawn := weightedPrompts(rng, prompts, weights, last, 2)
+		if len(drawn) != 2 || drawn[0] == drawn[1] {
+			t.Fatalf("drew %q, want two different prompts", drawn)
+		}
+		for _, prompt := range drawn {
+			if slices.Contains(last, prompt) {
+				t.Fatalf("drew %q again straight after %q", prompt, last)
+			}
+			counts[prompt]++
+		}
+		last = drawn
+	}
+	// Avoiding the last round's prompts evens things out, but the heavy
+	// prompt should still come up most and the light one least
+	if counts["Animals"] <= counts["Bands"] || counts["Animals"] <= counts["Cars"] {
+		t.Errorf("counts %v, want Animals ahead", counts)
+	}
+	if counts["Desserts"]*2 >= counts["Bands"] || counts["Desserts"]*2 >= counts["Cars"] {
+		t.Errorf("counts %v, want Desserts well behind", counts)
+	}
+	if drawn := weightedPrompts(rng, prompts, weights, nil, 10); len(drawn) != 6 {
+		t.Errorf("drawing 10 of 6 prompts gave %q, want all 6 once", drawn)
+	}
+}
+
+// lockedOpener fails to open anything with err the first fails times, then
+// opens contents, counting its calls.
+type lockedOpener struct {
+	err      error
+	fails    int
+	calls    int
+	contents string
+}
+
+func (o *lockedOpener) open(path string) (io.ReadCloser, error) {
+	o.calls++
+	if o.calls <= o.fails {
+		return nil, &os.PathError{Op: "open", Path: path, Err: o.err}
+	}
+	return io.NopCloser(strings.NewReader(o.contents)), nil
+}
+
+func TestOpenRetrying(t *testing.T) {
+	set(t, &OPEN_RETRIES, 3)
+	set(t, &OPEN_BACKOFF, time.Millisecond)
+	for _, test := range []struct {
+		name   string
+		err    error
+		fails  int
+		calls  int
+		opened bool
+	}{
+		{"opens first time", syscall.EBUSY, 0, 1, true},
+		{"locked for a moment", syscall.EBUSY, 2, 3, true},
+		{"locked for the last retry", syscall.EAGAIN, 3, 4, true},
+		{"stays locked", syscall.EBUSY, 10, 4, false},
+		{"missing", syscall.ENOENT, 10, 1, false},
+		{"forbidden", syscall.EACCES, 10, 1, false},
+	} {
+		opener := &lockedOpener{err: test.err, fails: test.fails}
+		file, err := openRetrying(opener.open, "prompts.txt")
+		if opener.calls != test.calls {
+			t.Errorf("%s: tried to open %d times, want %d", test.name, opener.calls, test.calls)
+		}
+		if (err == nil) != test.opened || (file != nil) != test.opened {
+			t.Errorf("%s: opened %v, %v; want opened %v", test.name, file != nil, err, test.opened)
+		}
+		if !test.opened && !errors.Is(err, test.err) {
+			t.Errorf("%s: error %v doesn't wrap %v", test.name, err, test.err)
+		}
+	}
+}
+
+func TestReadPromptsFileStaysLocked(t *testing.T) {
+	set(t, &OPEN_RETRIES, 2)
+	set(t, &OPEN_BACKOFF, time.Millisecond)
+	opener := &lockedOpener{err: syscall.EBUSY, fails: 10}
+	set(t, &openPromptsFile, opener.open)
+	_, err := readPromptsFile("prompts.txt")
+	if err == nil || !strings.Contains(err.Error(), "open in another program") {
+		t.Errorf("reading a locked prompts file gave %v, want it to say why", err)
+	}
+
+	opener = &lockedOpener{err: syscall.EBUSY, fails: 1, contents: "Animals\nFruits\n"}
+	set(t, &openPromptsFile, opener.open)
+	prompts, err := readPromptsFile("prompts.txt")
+	if err != nil || !slices.Equal(promptTexts(prompts), []string{"Animals", "Fruits"}) {
+		t.Errorf("reading a briefly locked prompts file gave %q, %v; want its prompts", promptTexts(prompts), err)
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Round is one round's draw along with anything collected while playing it.
+type Round struct {
+	Number  int
+	Letters []rune // answers can start with any of them
+	Prompts []string
+	Answers []Answer
+
+	SuddenDeath bool // played at half the time for double points
+	Replay      bool // a replay or practice round, so not drawn from the pools
+
+	LettersShuffled bool // the letter pool ran out and was reshuffled to draw it
+	PromptsShuffled bool // the prompt pool ran out and was reshuffled to draw it
+}
+
+// Answer is one player's response to one of a round's prompts.
+type Answer struct {
+	Player string
+	Prompt int // index into Round.Prompts
+	Text   string
+	Validation
+	Duplicate bool // another player gave the same answer
+	Repeat    bool // the same player already gave it for the same prompt
+	Unlisted  bool // not in the DICTIONARY
+	Rejected  bool // voted down by the players
+	Points    int
+}
+
+// Validation records whether an answer follows the round's letter rule, and
+// why not if it doesn't.
+type Validation struct {
+	Valid  bool
+	Reason string
+}
+
+// validateAnswer checks that text starts with one of letters, ignoring case
+// and surrounding whitespace. With skipArticles, a leading "the", "a" or "an"
+// is skipped before checking. With no letters, any answer will do.
+func validateAnswer(text string, letters []rune, skipArticles bool) Validation {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Validation{Reason: msg("no-answer")}
+	}
+	if skipArticles {
+		if words := strings.Fields(text); len(words) > 1 && isArticle(words[0]) {
+			text = strings.Join(words[1:], " ")
+		}
+	}
+	if len(letters) == 0 {
+		return Validation{Valid: true}
+	}
+	first, _ := utf8.DecodeRuneInString(text)
+	for _, letter := range letters {
+		if unicode.ToUpper(first) == unicode.ToUpper(letter) {
+			return Validation{Valid: true}
+		}
+	}
+	return Validation{Reason: fmt.Sprintf(msg("bad-start"), letterText(letters))}
+}
+
+// newAnswer records player's answer to round's prompt, checking it against
+// the round's letter and, if one is loaded, the DICTIONARY. With
+// STRICT_DICTIONARY, answers missing from it are invalid.
+func newAnswer(round *Round, player string, prompt int, text string) Answer {
+	answer := Answer{
+		Player:     player,
+		Prompt:     prompt,
+		Text:       strings.TrimSpace(text),
+		Validation: validateAnswer(text, round.Letters, SKIP_ARTICLES),
+	}
+	if DICTIONARY != nil && answer.Text != "" && !DICTIONARY.Contains(answer.Text, WHOLE_PHRASES, SKIP_ARTICLES) {
+		answer.Unlisted = true
+		if STRICT_DICTIONARY && answer.Valid {
+			answer.Validation = Validation{Reason: msg("unlisted")}
+		}
+	}
+	return answer
+}
+
+func isArticle(word string) bool {
+	for _, article := range ARTICLES {
+		if strings.EqualFold(word, article) {
+			return true
+		}
+	}
+	return false
+}
+
+// Play shows the round's letter and prompts, runs its timer, and then
+// collects answers if there are PLAYERS. With LetterLast the prompts come
+// first, and the letter only once a key is pressed, after the countdown,
+// with the timer starting as it appears. It returns how the timer stopped,
+// or QUIT if input ran out.
+func (g *Game) Play(round *Round, in *interrupter) timerResult {
+	fmt.Fprintln(g.out, SEP)
+	var reveal *stagger
+	switch {
+	case g.LetterLast:
+		fmt.Fprintln(g.out, msg("prompts"))
+		for i, prompt := range round.Prompts {
+			fmt.Fprintf(g.out, PROMPT_FORMAT+"\n", painted{COLOR_DIM, i + 1}, prompt)
+		}
+		fmt.Fprintln(g.out, "")
+		if _, ok := g.ask(msg("reveal")); !ok {
+			fmt.Fprintln(g.out, msg("bye"))
+			return QUIT
+		}
+		preRoundCountdown(in.startRound(), g.out, g.Clock, COUNTDOWN)
+		in.endRound()
+		printLetters(g.out, round.Letters)
+	case g.Stagger > 0:
+		printLetters(g.out, round.Letters)
+		// Hold the prompts back for the timer to reveal
+		reveal = newStagger(round.Prompts, g.Stagger)
+	default:
+		printLetters(g.out, round.Letters)
+		fmt.Fprintln(g.out, msg("prompts"))
+		for i, prompt := range round.Prompts {
+			fmt.Fprintf(g.out, PROMPT_FORMAT+"\n", painted{COLOR_DIM, i + 1}, prompt)
+		}
+		fmt.Fprintln(g.out, "")
+	}
+	printControls(g.out, "controls")
+	if reveal != nil {
+		fmt.Fprintf(g.out, msg("stagger")+"\n", g.Stagger)
+		fmt.Fprintln(g.out, msg("prompts"))
+	}
+
+	// Show timer until round ends or is interrupted
+	var view timerView = &lineView{}
+	if QUIET {
+		view = quietView{}
+	} else if TUI {
+		shown := len(round.Prompts)
+		if reveal != nil {
+			shown = 0
+		}
+		view = &tuiView{round: round, shown: shown}
+	}
+	timer := newRoundTimer(g.Duration, g.Clock.Now())
+	if g.Events != nil {
+		view = &emitView{timerView: view, events: g.Events, round: round.Number, timer: timer, clock: g.Clock, last: -1}
+	}
+	g.mu.Lock()
+	g.live = liveRound{round, timer}
+	g.mu.Unlock()
+	ctx := in.startRound()
+	result, elapsed := countdown(ctx, g.out, view, g.Clock, timer, g.Resolution, g.lines, reveal)
+	in.endRound()
+	g.mu.Lock()
+	g.live.timer = nil
+	g.Stats.record(*round, result, elapsed)
+	g.mu.Unlock()
+	switch result {
+	case TIME_UP:
+		fmt.Fprintln(g.out, paint(COLOR_RED, msg("times-up")))
+		ring(g.out, BELLS)
+	case ENDED_EARLY:
+		fmt.Fprintln(g.out, msg("ended-early"))
+	case REDRAWN:
+		fmt.Fprintln(g.out, msg("redrawing"))
+		return result
+	case UNDONE:
+		fmt.Fprintln(g.out, msg("undone"))
+		return result
+	case QUIT:
+		fmt.Fprintln(g.out, msg("bye"))
+		return result
+	}
+
+	collect := collectAnswers
+	if SHEETS {
+		collect = func(w io.Writer, round *Round, players []string, lines <-chan string) bool {
+			return collectSheets(w, g.Clock, round, players, lines, SHEET_TIME)
+		}
+	}
+	if len(PLAYERS) > 0 && !collect(g.out, round, PLAYERS, g.lines) {
+		return QUIT
+	}
+	return result
+}
+
+// printControls prints the keys that control the round's timer, worded by
+// the message key.
+func printControls(w io.Writer, key string) {
+	fmt.Fprintf(w, msg(key)+"\n", EXTEND_KEY, EXTEND_BY, SKIP_KEY, REDRAW_KEY, UNDO_KEY, UNDO_WINDOW, PAUSE_KEY, QUIT_KEY)
+}
+
+// printSummary recaps a finished round: its letter, its prompts (with an
+// example answer for each if HINTS are on) and, if answers were collected,
+// each player's count of valid unique answers.
+func printSummary(w io.Writer, round Round, players []string) {
+	fmt.Fprintf(w, msg("summary")+"\n", round.Number)
+	if len(round.Letters) > 0 {
+		fmt.Fprintf(w, "  "+msg("summary-letter")+"\n", letterText(round.Letters))
+	}
+	fmt.Fprintln(w, "  "+msg("prompts"))
+	for i, prompt := range round.Prompts {
+		fmt.Fprintf(w, "    %d.\t%s\n", i+1, prompt)
+		if !HINTS {
+			continue
+		}
+		if hint := EXAMPLES.Hint(prompt, round.Letters); hint == NO_EXAMPLE {
+			fmt.Fprintf(w, "      \t%s\n", msg("no-example"))
+		} else {
+			fmt.Fprintf(w, "      \t"+msg("example")+"\n", hint)
+		}
+	}
+	if len(round.Answers) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "  "+msg("summary-unique"))
+	for _, player := range players {
+		count := 0
+		for _, answer := range round.Answers {
+			if answer.Player == player && answer.Valid && !answer.Duplicate && !answer.Repeat {
+				count++
+			}
+		}
+		fmt.Fprintf(w, "    %s\t%d\n", player, count)
+	}
+}
+
+// collectAnswers asks each player for their answers to each prompt, reading
+// them from lines: one per prompt, or up to MAX_ANSWERS if that's more
+// than one. It reports false if input ran out before everyone answered.
+func collectAnswers(w io.Writer, round *Round, players []string, lines <-chan string) bool {
+	for _, player := range players {
+		if MAX_ANSWERS > 1 {
+			fmt.Fprintf(w, msg("answers-multi")+"\n", player, MAX_ANSWERS, forLetters(round.Letters))
+		} else {
+			fmt.Fprintf(w, msg("answers")+"\n", player, forLetters(round.Letters))
+		}
+		for i, prompt := range round.Prompts {
+			fmt.Fprintf(w, "  %d.\t%s: ", i+1, prompt)
+			if !answerPrompt(w, round, player, i, lines) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// answerPrompt reads player's answers to one of round's prompts from lines,
+// noting any that don't count, and adds them to the round. It reports false
+// if input ran out.
+func answerPrompt(w io.Writer, round *Round, player string, prompt int, lines <-chan string) bool {
+	texts, ok := readAnswers(w, lines, MAX_ANSWERS)
+	if !ok {
+		fmt.Fprintln(w)
+		return false
+	}
+	for _, text := range texts {
+		answer := newAnswer(round, player, prompt, text)
+		note := ""
+		if len(texts) > 1 {
+			note = answer.Text + ": "
+		}
+		if !answer.Valid && answer.Text != "" {
+			fmt.Fprintf(w, "\t"+msg("invalid")+"\n", note, answer.Reason)
+		} else if answer.Unlisted {
+			fmt.Fprintf(w, "\t"+msg("not-in-dict")+"\n", note)
+		}
+		round.Answers = append(round.Answers, answer)
+	}
+	return true
+}
+
+// readAnswers reads up to max answers to one prompt from lines. With a max of
+// one that's simply the next line; otherwise it's every comma-separated
+// answer on the lines up to a blank one, or up to max, dropping any extras.
+// A prompt given no answers gets a single blank one. It reports false if
+// input ran out.
+func readAnswers(w io.Writer, lines <-chan string, max int) ([]string, bool) {
+	if max <= 1 {
+		text, ok := <-lines
+		return []string{text}, ok
+	}
+	texts := []string{}
+	for len(texts) < max {
+		line, ok := <-lines
+		if !ok {
+			return texts, false
+		}
+		answers := splitList(line)
+		if len(answers) == 0 {
+			break
+		}
+		if len(texts)+len(answers) > max {
+			fmt.Fprintf(w, "\t"+msg("too-many")+"\n", max, strings.Join(answers[max-len(texts):], ", "))
+			answers = answers[:max-len(texts)]
+		}
+		texts = append(texts, answers...)
+		if len(texts) < max {
+			fmt.Fprint(w, "  \t...: ")
+		}
+	}
+	if len(texts) == 0 {
+		texts = append(texts, "")
+	}
+	return texts, true
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
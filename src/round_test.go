@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateAnswer(t *testing.T) {
+	for _, test := range []struct {
+		text         string
+		letters      string
+		skipArticles bool
+		want         Validation
+	}{
+		{"Bear", "B", false, Validation{Valid: true}},
+		{"bear", "B", false, Validation{Valid: true}},
+		{"  Bear  ", "b", false, Validation{Valid: true}},
+		{"Ant", "B", false, Validation{Reason: "doesn't start with B"}},
+		{"", "B", false, Validation{Reason: "no answer"}},
+		{"   ", "B", false, Validation{Reason: "no answer"}},
+		{"The Beatles", "B", false, Validation{Reason: "doesn't start with B"}},
+		{"The Beatles", "B", true, Validation{Valid: true}},
+		{"an  Bee", "B", true, Validation{Valid: true}},
+		{"The", "T", true, Validation{Valid: true}},
+		{"Theremin", "B", true, Validation{Reason: "doesn't start with B"}},
+		{"Cat", "ABC", false, Validation{Valid: true}},
+		{"Dog", "AB", false, Validation{Reason: "doesn't start with A or B"}},
+		{"Anything", "", false, Validation{Valid: true}},
+		{"élan", "É", false, Validation{Valid: true}},
+	} {
+		if got := validateAnswer(test.text, []rune(test.letters), test.skipArticles); got != test.want {
+			t.Errorf("validateAnswer(%q, %q, %v) = %+v, want %+v", test.text, test.letters, test.skipArticles, got, test.want)
+		}
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	round := roundWith("B", map[string][]string{
+		"Al": {"Bear", "Banana"},
+		"Bo": {"bear", "Apple"},
+	})
+	round.Number = 3
+	round.Prompts = []string{"Animals", "Fruits"}
+	scoreRound(&round, ScoringRules{Unique: 1})
+	var out bytes.Buffer
+	printSummary(&out, round, []string{"Al", "Bo"})
+	want := "Round 3 summary\n" +
+		"  Letter: B\n" +
+		"  Prompts:\n" +
+		"    1.\tAnimals\n" +
+		"    2.\tFruits\n" +
+		"  Valid unique answers:\n" +
+		"    Al\t1\n" +
+		"    Bo\t0\n"
+	if out.String() != want {
+		t.Errorf("printSummary wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPrintSummaryWithoutAnswers(t *testing.T) {
+	var out bytes.Buffer
+	printSummary(&out, Round{Number: 1, Letters: []rune("C"), Prompts: []string{"Animals"}}, []string{})
+	if want := "Round 1 summary\n  Letter: C\n  Prompts:\n    1.\tAnimals\n"; out.String() != want {
+		t.Errorf("printSummary wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestNoSummary(t *testing.T) {
+	set(t, &NO_SUMMARY, true)
+	set(t, &COUNTDOWN, 0)
+	set(t, &BELLS, 0)
+	var out bytes.Buffer
+	g := NewGame(Config{Duration: time.Second, NumPrompts: 1, Letters: []rune("AB"), Resolution: time.Second, Rounds: 2}, TEST_PROMPTS, 1, nil, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.Run(&interrupter{out: &out})
+	if strings.Contains(out.String(), "summary") {
+		t.Errorf("-no-summary still recapped rounds:\n%s", out.String())
+	}
+	NO_SUMMARY = false
+	out.Reset()
+	g = NewGame(g.Config, TEST_PROMPTS, 1, nil, &out)
+	g.Clock = clock
+	g.Run(&interrupter{out: &out})
+	if n := strings.Count(out.String(), "summary"); n != 2 {
+		t.Errorf("recapped %d of 2 rounds:\n%s", n, out.String())
+	}
+}
+
+func TestLetterLastOrder(t *testing.T) {
+	set(t, &QUIET, true)
+	set(t, &BELLS, 0)
+	set(t, &COUNTDOWN, 2)
+	set(t, &LETTER_FORMAT, "Letter: %s")
+	lines := make(chan string)
+	var out bytes.Buffer
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("K"), LetterLast: true, Resolution: time.Second}, TEST_PROMPTS, 1, lines, &out)
+	clock := newFakeClock()
+	g.Clock = clock
+	round := g.NextRound()
+	done := make(chan timerResult, 1)
+	go func() { done <- g.Play(&round, &interrupter{out: &out}) }()
+
+	lines <- "" // reveal the letter
+	for i := 0; i < COUNTDOWN; i++ {
+		clock.BlockUntil(t, 1)
+		clock.Advance(time.Second)
+	}
+	// The clock starts as the letter appears, not before the countdown
+	clock.BlockUntil(t, 1)
+	clock.Advance(5 * time.Second)
+	clock.BlockUntil(t, 1)
+	lines <- SKIP_KEY
+	if result := <-done; result != ENDED_EARLY {
+		t.Fatalf("round ended with %v, want ended early", result)
+	}
+	if g.Stats.TimePlayed != 5*time.Second {
+		t.Errorf("timed %s, want 5s from the letter appearing", g.Stats.TimePlayed)
+	}
+
+	order := []string{SEP, msg("prompts"), round.Prompts[0], round.Prompts[1], msg("reveal"), "2...", "1...", msg("go"), "Letter: K", msg("ended-early")}
+	at := 0
+	for _, want := range order {
+		i := strings.Index(out.String()[at:], want)
+		if i < 0 {
+			t.Fatalf("no %q after %q in:\n%s", want, out.String()[:at], out.String())
+		}
+		at += i + len(want)
+	}
+	if strings.Count(out.String(), "Letter: ") != 1 {
+		t.Errorf("showed the letter more than once:\n%s", out.String())
+	}
+}
+
+func TestReadAnswers(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		lines []string
+		max   int
+		want  []string
+		ok    bool
+		left  int // lines still unread
+	}{
+		{"one", []string{"Bear, Bison", "Boar"}, 1, []string{"Bear, Bison"}, true, 1},
+		{"comma separated", []string{"Bear, Bison,Boar", "Bat"}, 3, []string{"Bear", "Bison", "Boar"}, true, 1},
+		{"a line each", []string{"Bear", "Bison", "Boar", "Bat"}, 3, []string{"Bear", "Bison", "Boar"}, true, 1},
+		{"ended by a blank line", []string{"Bear", " ", "Bat"}, 3, []string{"Bear"}, true, 1},
+		{"too many", []string{"Bear, Bison, Boar"}, 2, []string{"Bear", "Bison"}, true, 0},
+		{"none", []string{""}, 3, []string{""}, true, 0},
+		{"input runs out", []string{"Bear"}, 3, []string{"Bear"}, false, 0},
+	} {
+		lines := make(chan string, len(test.lines))
+		for _, line := range test.lines {
+			lines <- line
+		}
+		close(lines)
+		var out bytes.Buffer
+		got, ok := readAnswers(&out, lines, test.max)
+		if strings.Join(got, "|") != strings.Join(test.want, "|") || ok != test.ok || len(lines) != test.left {
+			t.Errorf("%s: read %q, %v, leaving %d lines; want %q, %v, leaving %d", test.name, got, ok, len(lines), test.want, test.ok, test.left)
+		}
+		if turnedDown := strings.Contains(out.String(), "Boar"); turnedDown != (test.name == "too many") {
+			t.Errorf("%s: said %q", test.name, out.String())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// normalizeAnswer reduces an answer to the form used to compare it against
+// other players' answers, as set by NORMALIZE.
+func normalizeAnswer(text string) string {
+	return NORMALIZE.Apply(text)
+}
+
+// ScoringRules sets what answers are worth. The zero bonus and duplicate
+// points give the standard rules: a point per valid unique answer.
+type ScoringRules struct {
+	Unique       int // points for a valid answer no other player gave
+	Duplicate    int // points for a valid answer another player also gave
+	Alliteration int // bonus on a valid unique answer of two or more words all starting with the letter
+}
+
+// Scorer is a scoring policy: what a round's answers are worth. Score is
+// called once per scored round, after the answers have been collected,
+// checked against the letter and voted on. It must set each answer's
+// Duplicate, Repeat and Points, since the breakdown printed and exported
+// afterwards shows them, give no points to answers that aren't Valid or were
+// Rejected, and return each player's total for the round, which should be
+// the sum of their answers' Points. Players who scored nothing can be left
+// out of the totals.
+type Scorer interface {
+	Score(round *Round) map[string]int
+}
+
+// newScorer returns the built-in scorer called name, awarding points by
+// rules.
+func newScorer(name string, rules ScoringRules) (Scorer, error) {
+	switch name {
+	case "standard":
+		return standardScorer{rules}, nil
+	case "words":
+		return wordsScorer{rules}, nil
+	}
+	return nil, fmt.Errorf("%q isn't standard or words", name)
+}
+
+// standardScorer scores by the usual rules: see scoreRound.
+type standardScorer struct {
+	rules ScoringRules
+}
+
+func (s standardScorer) Score(round *Round) map[string]int {
+	return scoreRound(round, s.rules)
+}
+
+// wordsScorer scores the way the box suggests: a unique answer is worth the
+// Unique points for each of its words that starts with the letter, so "Big
+// Bad Bear" scores three times over for B. Duplicates score as usual, and
+// there's no separate alliteration bonus.
+type wordsScorer struct {
+	rules ScoringRules
+}
+
+func (s wordsScorer) Score(round *Round) map[string]int {
+	scoreRound(round, ScoringRules{Unique: s.rules.Unique, Duplicate: s.rules.Duplicate})
+	totals := map[string]int{}
+	for i := range round.Answers {
+		answer := &round.Answers[i]
+		if answer.Points > 0 && !answer.Duplicate {
+			answer.Points *= max(letterWords(answer.Text, round.Letters), 1)
+		}
+		totals[answer.Player] += answer.Points
+	}
+	return totals
+}
+
+// scaledScorer multiplies everything another Scorer awards by n.
+type scaledScorer struct {
+	Scorer
+	n int
+}
+
+func (s scaledScorer) Score(round *Round) map[string]int {
+	totals := s.Scorer.Score(round)
+	for i := range round.Answers {
+		round.Answers[i].Points *= s.n
+	}
+	for player := range totals {
+		totals[player] *= s.n
+	}
+	return totals
+}
+
+// scoreRound marks answers that more than one player gave for the same prompt
+// as duplicates, and answers a player gave more than once for the same
+// prompt as repeats, awards points by rules to each valid answer the players
+// didn't reject (once only, for repeats), and returns the round's total per
+// player.
+func scoreRound(round *Round, rules ScoringRules) map[string]int {
+	type key struct {
+		prompt int
+		text   string
+	}
+	players := map[key]map[string]bool{}
+	for _, answer := range round.Answers {
+		if answer.Text == "" {
+			continue
+		}
+		k := key{answer.Prompt, normalizeAnswer(answer.Text)}
+		if players[k] == nil {
+			players[k] = map[string]bool{}
+		}
+		players[k][answer.Player] = true
+	}
+
+	totals := map[string]int{}
+	given := map[key]map[string]bool{} // answers counted so far, by player
+	for i := range round.Answers {
+		answer := &round.Answers[i]
+		k := key{answer.Prompt, normalizeAnswer(answer.Text)}
+		answer.Duplicate = len(players[k]) > 1
+		answer.Repeat = answer.Text != "" && given[k][answer.Player]
+		if given[k] == nil {
+			given[k] = map[string]bool{}
+		}
+		given[k][answer.Player] = true
+		answer.Points = 0
+		switch {
+		case !answer.Valid || answer.Rejected || answer.Repeat:
+		case answer.Duplicate:
+			answer.Points = rules.Duplicate
+		default:
+			answer.Points = rules.Unique
+			if alliterates(answer.Text, round.Letters) {
+				answer.Points += rules.Alliteration
+			}
+		}
+		totals[answer.Player] += answer.Points
+	}
+	return totals
+}
+
+// alliterates reports whether text has two or more words and all of them
+// start with one of letters, ignoring any leading article if SKIP_ARTICLES is
+// set.
+func alliterates(text string, letters []rune) bool {
+	words := answerWords(text)
+	return len(words) >= 2 && len(letters) > 0 && letterWords(text, letters) == len(words)
+}
+
+// letterWords counts the words of text that start with one of letters,
+// ignoring any leading article if SKIP_ARTICLES is set.
+func letterWords(text string, letters []rune) int {
+	if len(letters) == 0 {
+		return 0
+	}
+	n := 0
+	for _, word := range answerWords(text) {
+		if validateAnswer(word, letters, false).Valid {
+			n++
+		}
+	}
+	return n
+}
+
+// answerWords splits text into words, dropping a leading article if
+// SKIP_ARTICLES is set.
+func answerWords(text string) []string {
+	words := strings.Fields(text)
+	if SKIP_ARTICLES && len(words) > 1 && isArticle(words[0]) {
+		words = words[1:]
+	}
+	return words
+}
+
+// printScores prints each player's points for the round, in player order.
+func printScores(w io.Writer, round Round, players []string, totals map[string]int) {
+	fmt.Fprintf(w, msg("scores")+"\n", round.Number)
+	for _, player := range players {
+		fmt.Fprintf(w, "  %s\t%d\n", player, totals[player])
+		for _, answer := range round.Answers {
+			if answer.Player != player || answer.Text == "" {
+				continue
+			}
+			note := ""
+			if !answer.Valid {
+				note = " (" + answer.Reason + ")"
+			} else if answer.Repeat {
+				note = " (" + msg("repeat") + ")"
+			} else if answer.Rejected {
+				note = " (" + msg("rejected") + ")"
+			} else if answer.Duplicate {
+				note = " (" + msg("duplicate") + ")"
+			} else if answer.Unlisted {
+				note = " (" + msg("unlisted") + ")"
+			}
+			fmt.Fprintf(w, "    %d. %s: %d%s\n", answer.Prompt+1, answer.Text, answer.Points, note)
+		}
+	}
+}
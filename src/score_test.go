@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// roundWith makes a round for letter with each player's answers, one per
+// prompt in order.
+func roundWith(letter string, answers map[string][]string) Round {
+	round := Round{Number: 1, Letters: []rune(letter), Prompts: []string{}}
+	for _, player := range sortedNames(answers) {
+		for i, text := range answers[player] {
+			for len(round.Prompts) <= i {
+				round.Prompts = append(round.Prompts, "Prompt")
+			}
+			round.Answers = append(round.Answers, newAnswer(&round, player, i, text))
+		}
+	}
+	return round
+}
+
+func sortedNames[T any](m map[string]T) []string {
+	names := []string{}
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestScoreRoundDuplicates(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		answers map[string][]string
+		want    map[string]int
+	}{
+		{"no overlap", map[string][]string{
+			"Al": {"Bear", "Banana"},
+			"Bo": {"Bat", "Blueberry"},
+		}, map[string]int{"Al": 2, "Bo": 2}},
+		{"overlap ignoring case and space", map[string][]string{
+			"Al": {"Bear", "Banana"},
+			"Bo": {" bear ", "Blueberry"},
+			"Cy": {"Bison", "BANANA"},
+		}, map[string]int{"Al": 0, "Bo": 1, "Cy": 1}},
+		{"same answer to different prompts", map[string][]string{
+			"Al": {"Bee", ""},
+			"Bo": {"", "Bee"},
+		}, map[string]int{"Al": 1, "Bo": 1}},
+		{"invalid and blank answers", map[string][]string{
+			"Al": {"Apple", ""},
+			"Bo": {"Apple", "Berry"},
+		}, map[string]int{"Al": 0, "Bo": 1}},
+	} {
+		round := roundWith("B", test.answers)
+		totals := scoreRound(&round, ScoringRules{Unique: 1})
+		for _, player := range sortedNames(test.want) {
+			if totals[player] != test.want[player] {
+				t.Errorf("%s: %s scored %d, want %d", test.name, player, totals[player], test.want[player])
+			}
+		}
+	}
+}
+
+func TestScoreRoundRules(t *testing.T) {
+	round := roundWith("B", map[string][]string{
+		"Al": {"Bear", "Big Brown Bat", "Bee"},
+		"Bo": {"Bear", "Bat", "Bee"},
+	})
+	round.Answers = append(round.Answers, newAnswer(&round, "Bo", 2, "bee"))
+	totals := scoreRound(&round, ScoringRules{Unique: 10, Duplicate: 5, Alliteration: 3})
+	if totals["Al"] != 5+13+5 || totals["Bo"] != 5+10+5 {
+		t.Errorf("totals = %v, want Al 23 and Bo 20", totals)
+	}
+	repeat := round.Answers[len(round.Answers)-1]
+	if !repeat.Repeat || repeat.Points != 0 {
+		t.Errorf("Bo's second Bee = %+v, want a repeat worth nothing", repeat)
+	}
+}
+
+func TestScoringRules(t *testing.T) {
+	// Al's Big Bear alliterates; Bo and Cy both give Bison; Cy's Apple is
+	// invalid.
+	answers := map[string][]string{
+		"Al": {"Big Bear", "Banana"},
+		"Bo": {"Bison", "Blueberry"},
+		"Cy": {"bison", "Apple"},
+	}
+	for _, test := range []struct {
+		name  string
+		rules ScoringRules
+		want  map[string]int
+	}{
+		{"standard", ScoringRules{Unique: 1}, map[string]int{"Al": 2, "Bo": 1, "Cy": 0}},
+		{"two for unique", ScoringRules{Unique: 2}, map[string]int{"Al": 4, "Bo": 2, "Cy": 0}},
+		{"a point for duplicates", ScoringRules{Unique: 2, Duplicate: 1}, map[string]int{"Al": 4, "Bo": 3, "Cy": 1}},
+		{"alliteration bonus", ScoringRules{Unique: 1, Alliteration: 2}, map[string]int{"Al": 4, "Bo": 1, "Cy": 0}},
+		{"everything", ScoringRules{Unique: 3, Duplicate: 1, Alliteration: 1}, map[string]int{"Al": 7, "Bo": 4, "Cy": 1}},
+		{"nothing", ScoringRules{}, map[string]int{"Al": 0, "Bo": 0, "Cy": 0}},
+	} {
+		round := roundWith("B", answers)
+		totals := scoreRound(&round, test.rules)
+		for _, player := range sortedNames(test.want) {
+			if totals[player] != test.want[player] {
+				t.Errorf("%s: %s scored %d, want %d", test.name, player, totals[player], test.want[player])
+			}
+		}
+	}
+}
+
+func TestAlliterates(t *testing.T) {
+	set(t, &SKIP_ARTICLES, true)
+	for _, test := range []struct {
+		text, letters string
+		want          bool
+	}{
+		{"Big Bear", "B", true},
+		{"big bad bear", "B", true},
+		{"Bear", "B", false},
+		{"Big Cat", "B", false},
+		{"Big Cat", "BC", true},
+		{"The Big Bear", "B", true},
+		{"Big Bear", "", false},
+	} {
+		if got := alliterates(test.text, []rune(test.letters)); got != test.want {
+			t.Errorf("alliterates(%q, %q) = %v, want %v", test.text, test.letters, got, test.want)
+		}
+	}
+}
+
+func TestScoreMultipleAnswers(t *testing.T) {
+	set(t, &QUIET, true)
+	set(t, &MAX_ANSWERS, 3)
+	// Al and Bo answer both prompts with up to three answers each
+	script := strings.Join([]string{"Bear, Bison, bear", "Banana", "", "Bison", "Boar, Cat", "Blueberry, Banana", ""}, "\n") + "\n"
+	round := Round{Number: 1, Letters: []rune("B"), Prompts: []string{"Animals", "Fruits"}}
+	if !collectAnswers(io.Discard, &round, []string{"Al", "Bo"}, readLines(strings.NewReader(script))) {
+		t.Fatal("input ran out")
+	}
+	totals := scoreRound(&round, ScoringRules{Unique: 2, Duplicate: 1})
+	got := []string{}
+	for _, answer := range round.Answers {
+		got = append(got, fmt.Sprintf("%s %d %s %d", answer.Player, answer.Prompt+1, answer.Text, answer.Points))
+	}
+	want := []string{
+		"Al 1 Bear 2", "Al 1 Bison 1", "Al 1 bear 0", // a repeat scores nothing
+		"Al 2 Banana 1",
+		"Bo 1 Bison 1", "Bo 1 Boar 2", "Bo 1 Cat 0",
+		"Bo 2 Blueberry 2", "Bo 2 Banana 1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("scored\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if totals["Al"] != 4 || totals["Bo"] != 6 {
+		t.Errorf("totals %v, want Al on 4 and Bo on 6", totals)
+	}
+	if !round.Answers[2].Repeat || round.Answers[1].Repeat || !round.Answers[1].Duplicate {
+		t.Errorf("marked %+v and %+v, want the second Bear a repeat and Bison a duplicate", round.Answers[1], round.Answers[2])
+	}
+}
+
+func TestScorers(t *testing.T) {
+	rules := ScoringRules{Unique: 1, Duplicate: 0, Alliteration: 2}
+	answers := map[string][]string{
+		"Al": {"Big red Bear", "Banana", "apple", "Bouncing Baby Boy"},
+		"Bo": {"Bat", "banana", "", "Bread"},
+	}
+	standard, err := newScorer("standard", rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words, err := newScorer("words", rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name   string
+		scorer Scorer
+		points map[string][]int // by player, per prompt
+	}{
+		// Only "Bouncing Baby Boy" alliterates, for the bonus
+		{"standard", standard, map[string][]int{"Al": {1, 0, 0, 3}, "Bo": {1, 0, 0, 1}}},
+		// A point for every word starting with B, and no bonus
+		{"words", words, map[string][]int{"Al": {2, 0, 0, 3}, "Bo": {1, 0, 0, 1}}},
+		{"doubled", scaledScorer{standard, 2}, map[string][]int{"Al": {2, 0, 0, 6}, "Bo": {2, 0, 0, 2}}},
+	} {
+		round := roundWith("B", answers)
+		totals := test.scorer.Score(&round)
+		sums := map[string]int{}
+		for _, answer := range round.Answers {
+			if want := test.points[answer.Player][answer.Prompt]; answer.Points != want {
+				t.Errorf("%s: %s's %q scored %d, want %d", test.name, answer.Player, answer.Text, answer.Points, want)
+			}
+			if answer.Points != 0 && (!answer.Valid || answer.Duplicate) {
+				t.Errorf("%s: %s's %q scored despite being invalid or a duplicate", test.name, answer.Player, answer.Text)
+			}
+			sums[answer.Player] += answer.Points
+		}
+		for player, sum := range sums {
+			if totals[player] != sum {
+				t.Errorf("%s: %s's total is %d, but their answers add up to %d", test.name, player, totals[player], sum)
+			}
+		}
+	}
+	if _, err := newScorer("golf", rules); err == nil {
+		t.Error("made a scorer with an unknown name")
+	}
+}
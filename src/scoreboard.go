@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"sort"
+)
+
+// Scoreboard keeps each player's running total across rounds, and across
+// sessions when saved to a file.
+type Scoreboard struct {
+	Rounds int            `json:"rounds"`
+	Totals map[string]int `json:"totals"`
+
+	// Duplicates counts each player's answers that someone else also gave,
+	// for the duplicates tiebreak.
+	Duplicates map[string]int `json:"duplicates,omitempty"`
+
+	// Playoff is each tied player's points from this game's -tiebreak prompt
+	// playoff. It isn't saved, so a past game's playoff can't settle ties in
+	// a later one.
+	Playoff map[string]int `json:"-"`
+}
+
+// clone returns a copy of s that shares nothing with it, to read while s
+// goes on changing.
+func (s *Scoreboard) clone() *Scoreboard {
+	c := *s
+	c.Totals = maps.Clone(s.Totals)
+	c.Duplicates = maps.Clone(s.Duplicates)
+	c.Playoff = maps.Clone(s.Playoff)
+	return &c
+}
+
+// Standing is one row of a sorted scoreboard.
+type Standing struct {
+	Player string
+	Points int
+}
+
+func NewScoreboard() *Scoreboard {
+	return &Scoreboard{Totals: map[string]int{}, Duplicates: map[string]int{}}
+}
+
+// loadScoreboard reads standings saved at path. A missing or unreadable file
+// starts a fresh scoreboard instead.
+func loadScoreboard(path string) *Scoreboard {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewScoreboard()
+	} else if err != nil {
+		slog.Warn("can't read standings, starting fresh", "path", path, "err", err)
+		return NewScoreboard()
+	}
+	board := NewScoreboard()
+	if err := json.Unmarshal(data, board); err != nil {
+		slog.Warn("standings are corrupt, starting fresh", "path", path, "err", err)
+		return NewScoreboard()
+	}
+	if board.Totals == nil {
+		board.Totals = map[string]int{}
+	}
+	if board.Duplicates == nil {
+		board.Duplicates = map[string]int{}
+	}
+	return board
+}
+
+func (s *Scoreboard) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// AddRound adds one round's points to the running totals.
+func (s *Scoreboard) AddRound(points map[string]int) {
+	s.Rounds++
+	for player, n := range points {
+		s.Totals[player] += n
+	}
+}
+
+// AddDuplicates counts the round's duplicate answers against the players who
+// gave them.
+func (s *Scoreboard) AddDuplicates(answers []Answer) {
+	for _, answer := range answers {
+		if answer.Duplicate {
+			s.Duplicates[answer.Player]++
+		}
+	}
+}
+
+// TIEBREAKS are the ways -tiebreak can order players level on points:
+// alphabetically by name, by fewest duplicate answers, or by a playoff
+// prompt at the end of the game. Whichever is chosen, players still level
+// after it are ordered alphabetically, so the standings never depend on map
+// order.
+var TIEBREAKS = []string{"alphabetical", "duplicates", "prompt"}
+
+// Standings returns players ordered by total points, highest first, with
+// ties broken by TIEBREAK.
+func (s *Scoreboard) Standings() []Standing {
+	standings := []Standing{}
+	for player, points := range s.Totals {
+		standings = append(standings, Standing{player, points})
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		return ranksAbove(standings[i], standings[j], s.Duplicates, s.Playoff)
+	})
+	return standings
+}
+
+// ranksAbove reports whether a belongs above b in the standings: on more
+// points, then by TIEBREAK, using the duplicates and playoff points counted
+// under each name, then alphabetically.
+func ranksAbove(a, b Standing, duplicates, playoff map[string]int) bool {
+	switch {
+	case a.Points != b.Points:
+		return a.Points > b.Points
+	case TIEBREAK == "duplicates" && duplicates[a.Player] != duplicates[b.Player]:
+		return duplicates[a.Player] < duplicates[b.Player]
+	case TIEBREAK == "prompt" && playoff[a.Player] != playoff[b.Player]:
+		return playoff[a.Player] > playoff[b.Player]
+	}
+	return a.Player < b.Player
+}
+
+// Print prints the standings, by team if there are TEAMS.
+func (s *Scoreboard) Print(w io.Writer) {
+	if len(TEAMS) > 0 {
+		s.printTeams(w, TEAMS)
+		return
+	}
+	fmt.Fprintf(w, msg("standings")+"\n", s.Rounds)
+	for i, standing := range s.Standings() {
+		fmt.Fprintf(w, "  %d.\t%s\t%d\n", i+1, standing.Player, standing.Points)
+	}
+}
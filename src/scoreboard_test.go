@@ -0,0 +1,83 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestScoreboardAccumulates(t *testing.T) {
+	board := NewScoreboard()
+	board.AddRound(map[string]int{"Al": 3, "Bo": 5})
+	board.AddRound(map[string]int{"Al": 4, "Cy": 1})
+	if board.Rounds != 2 {
+		t.Errorf("rounds = %d, want 2", board.Rounds)
+	}
+	want := []Standing{{"Al", 7}, {"Bo", 5}, {"Cy", 1}}
+	if got := board.Standings(); !slices.Equal(got, want) {
+		t.Errorf("standings = %v, want %v", got, want)
+	}
+}
+
+func TestScoreboardRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "standings.json")
+	board := NewScoreboard()
+	board.AddRound(map[string]int{"Al": 3, "Bo": 5})
+	board.Duplicates["Al"] = 2
+	if err := board.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := loadScoreboard(path)
+	if loaded.Rounds != 1 || !maps.Equal(loaded.Totals, board.Totals) || !maps.Equal(loaded.Duplicates, board.Duplicates) {
+		t.Errorf("loaded %+v, want %+v", loaded, board)
+	}
+	loaded.AddRound(map[string]int{"Al": 1})
+	if loaded.Totals["Al"] != 4 || loaded.Rounds != 2 {
+		t.Errorf("carrying on from the saved standings gave %+v", loaded)
+	}
+}
+
+func TestLoadScoreboardStartsFresh(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(dir, "missing.json"), corrupt} {
+		board := loadScoreboard(path)
+		if board.Rounds != 0 || len(board.Totals) != 0 {
+			t.Errorf("%s loaded %+v, want fresh standings", filepath.Base(path), board)
+		}
+		board.AddRound(map[string]int{"Al": 1}) // the maps are ready to use
+	}
+}
+
+func TestTiebreaks(t *testing.T) {
+	board := NewScoreboard()
+	board.Totals = map[string]int{"Di": 3, "Bo": 3, "Cy": 5, "Al": 3}
+	board.Duplicates = map[string]int{"Al": 2, "Di": 1}
+	board.Playoff = map[string]int{"Di": 1}
+	for _, test := range []struct {
+		tiebreak string
+		want     []string
+	}{
+		{"alphabetical", []string{"Cy", "Al", "Bo", "Di"}},
+		{"duplicates", []string{"Cy", "Bo", "Di", "Al"}},
+		{"prompt", []string{"Cy", "Di", "Al", "Bo"}}, // Al and Bo still level
+	} {
+		set(t, &TIEBREAK, test.tiebreak)
+		// Over and over, since map order changes from one run to the next
+		for range 20 {
+			got := []string{}
+			for _, standing := range board.Standings() {
+				got = append(got, standing.Player)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("%s: standings %v, want %v", test.tiebreak, got, test.want)
+				break
+			}
+		}
+	}
+}
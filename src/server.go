@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// roundState is the /api/round payload.
+type roundState struct {
+	Active    bool     `json:"active"` // whether the timer is running
+	Seed      int64    `json:"seed"`
+	Round     int      `json:"round"`
+	Letter    string   `json:"letter"`
+	Prompts   []string `json:"prompts"`
+	Remaining int      `json:"remaining_seconds"`
+	Total     int      `json:"total_seconds"`
+	Paused    bool     `json:"paused"`
+}
+
+// State snapshots the current round for the web view.
+func (g *Game) State() roundState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	state := roundState{Seed: g.Seed, Prompts: []string{}}
+	if g.live.round == nil {
+		return state
+	}
+	state.Round = g.live.round.Number
+	state.Letter = letterText(g.live.round.Letters)
+	state.Prompts = g.live.round.Prompts
+	if g.live.timer != nil {
+		state.Active = true
+		state.Remaining = remainingSeconds(g.live.timer.Remaining(g.Clock.Now()))
+		state.Total = int(g.live.timer.Total().Seconds())
+		state.Paused = g.live.timer.Paused() || clockPaused(g.Clock)
+	}
+	return state
+}
+
+// apiError is the body of every failed API response.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// newServer serves a page showing the current round at / and a JSON API for
+// following and driving the game:
+//
+//	GET  /api/round        the current round's state
+//	POST /api/round/start  start the next round from the menu, as if the host pressed enter
+//	POST /api/round/end    end the running round early
+//
+// If API_TOKEN is set, the POST endpoints need it as a bearer token.
+func newServer(addr string, g *Game, in *interrupter) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := roundPage.Execute(w, pageText()); err != nil {
+			slog.Warn("writing round page", "err", err)
+		}
+	})
+	mux.HandleFunc("GET /api/round", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, g.State())
+	})
+	mux.HandleFunc("POST /api/round/start", authorized(func(w http.ResponseWriter, r *http.Request) {
+		if g.State().Active {
+			writeJSON(w, http.StatusConflict, apiError{"a round is already running"})
+			return
+		}
+		// Only a menu waiting for a choice takes the start, so none is left
+		// over for some later menu to find
+		select {
+		case g.starts <- struct{}{}:
+			writeJSON(w, http.StatusAccepted, g.State())
+		default:
+			writeJSON(w, http.StatusConflict, apiError{"no menu is waiting to start a round"})
+		}
+	}))
+	mux.HandleFunc("POST /api/round/end", authorized(func(w http.ResponseWriter, r *http.Request) {
+		// Not during the countdown or a letter yet to be revealed, which
+		// aren't the round's to end
+		if !g.State().Active || !in.cancelRound() {
+			writeJSON(w, http.StatusConflict, apiError{"no round is running"})
+			return
+		}
+		writeJSON(w, http.StatusOK, g.State())
+	}))
+	return &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+}
+
+// authorized rejects requests without the API_TOKEN bearer token, if set.
+func authorized(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if API_TOKEN != "" && r.Header.Get("Authorization") != "Bearer "+API_TOKEN {
+			writeJSON(w, http.StatusUnauthorized, apiError{"missing or wrong token"})
+			return
+		}
+		h(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Warn("writing API response", "err", err)
+	}
+}
+
+// ROUND_PAGE polls /api/round and shows the round it describes, in the
+// words pageText gives it.
+const ROUND_PAGE = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Scattergories</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 1em auto; padding: 0 1em; }
+#letter { font-size: 5em; font-weight: bold; text-align: center; margin: 0.2em 0; }
+#timer { font-size: 2em; text-align: center; }
+</style>
+</head>
+<body>
+<h1 id="title">Scattergories</h1>
+<div id="letter"></div>
+<div id="timer">{{.Waiting}}</div>
+<ol id="prompts"></ol>
+<script>
+async function refresh() {
+  try {
+    const state = await (await fetch("/api/round")).json();
+    if (state.round) {
+      document.getElementById("title").textContent = {{.Round}}.replace("%d", state.round);
+      document.getElementById("letter").textContent = state.letter;
+      const list = document.getElementById("prompts");
+      list.replaceChildren(...state.prompts.map(p => {
+        const item = document.createElement("li");
+        item.textContent = p;
+        return item;
+      }));
+      const secs = state.remaining_seconds;
+      const clock = Math.floor(secs / 60) + "m" + (secs % 60) + "s";
+      document.getElementById("timer").textContent =
+        !state.active ? {{.TimesUp}} : state.paused ? {{.Paused}}.replace("%s", clock) : clock;
+    }
+  } catch (e) {}
+  setTimeout(refresh, 1000);
+}
+refresh();
+</script>
+</body>
+</html>
+`
+
+var roundPage = template.Must(template.New("round").Parse(ROUND_PAGE))
+
+// pageText is the LANGUAGE text ROUND_PAGE shows.
+func pageText() map[string]string {
+	return map[string]string{
+		"Lang":    LANGUAGE,
+		"Waiting": msg("page-waiting"),
+		"Round":   msg("round"),
+		"TimesUp": msg("times-up"),
+		"Paused":  msg("page-paused"),
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveTest sends a request for path to the game's server and returns the
+// response.
+func serveTest(t *testing.T, handler http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+// decodeTest decodes a JSON response body into a T.
+func decodeTest[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var body T
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type %q, want application/json", ct)
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding %T: %v", body, err)
+	}
+	return body
+}
+
+func TestServeRoundState(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 2, Letters: []rune("B")}, TEST_PROMPTS)
+	g.Seed = 42
+	clock := newFakeClock()
+	g.Clock = clock
+	handler := newServer(":0", g, &interrupter{}).Handler
+
+	w := serveTest(t, handler, "GET", "/api/round", "")
+	if state := decodeTest[roundState](t, w); w.Code != http.StatusOK || state.Active || state.Round != 0 || state.Seed != 42 || state.Prompts == nil {
+		t.Errorf("before any round: %d %+v, want 200, inactive, seed 42 and no prompts", w.Code, state)
+	}
+
+	round := g.NextRound()
+	g.live = liveRound{&round, newRoundTimer(time.Minute, clock.Now())}
+	clock.Advance(15 * time.Second)
+	w = serveTest(t, handler, "GET", "/api/round", "")
+	want := roundState{Active: true, Seed: 42, Round: 1, Letter: "B", Prompts: round.Prompts, Remaining: 45, Total: 60}
+	if state := decodeTest[roundState](t, w); !state.Active || state.Round != want.Round || state.Letter != want.Letter ||
+		strings.Join(state.Prompts, "|") != strings.Join(want.Prompts, "|") || state.Remaining != want.Remaining || state.Total != want.Total || state.Paused {
+		t.Errorf("during round 1 state is %+v, want %+v", state, want)
+	}
+
+	w = serveTest(t, handler, "GET", "/", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/api/round") {
+		t.Errorf("GET / = %d, without the round page", w.Code)
+	}
+}
+
+func TestServeRoundControl(t *testing.T) {
+	set(t, &API_TOKEN, "secret")
+	g := newTestGame(Config{NumPrompts: 2, Letters: []rune("B")}, TEST_PROMPTS)
+	in := &interrupter{}
+	handler := newServer(":0", g, in).Handler
+
+	for _, path := range []string{"/api/round/start", "/api/round/end"} {
+		for _, token := range []string{"", "wrong"} {
+			w := serveTest(t, handler, "POST", path, token)
+			if body := decodeTest[apiError](t, w); w.Code != http.StatusUnauthorized || body.Error == "" {
+				t.Errorf("POST %s with token %q = %d %+v, want 401 and an error", path, token, w.Code, body)
+			}
+		}
+	}
+	if w := serveTest(t, handler, "GET", "/api/round", ""); w.Code != http.StatusOK {
+		t.Errorf("GET /api/round without a token = %d, want 200", w.Code)
+	}
+
+	w := serveTest(t, handler, "POST", "/api/round/start", "secret")
+	if body := decodeTest[apiError](t, w); w.Code != http.StatusConflict || body.Error == "" {
+		t.Errorf("starting a round with no menu waiting = %d %+v, want 409 and an error", w.Code, body)
+	}
+	started := make(chan bool)
+	go func() {
+		_, ok := g.Menu()
+		started <- ok
+	}()
+	// The menu takes the start once it's waiting for a choice
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if w := serveTest(t, handler, "POST", "/api/round/start", "secret"); w.Code == http.StatusAccepted {
+			break
+		} else if w.Code != http.StatusConflict || time.Now().After(deadline) {
+			t.Fatalf("starting a round from the menu = %d, want 202", w.Code)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !<-started {
+		t.Error("the start didn't start a round from the menu")
+	}
+	if w := serveTest(t, handler, "POST", "/api/round/start", "secret"); w.Code != http.StatusConflict {
+		t.Errorf("starting a round once the menu's gone = %d, want 409", w.Code)
+	}
+
+	w = serveTest(t, handler, "POST", "/api/round/end", "secret")
+	if body := decodeTest[apiError](t, w); w.Code != http.StatusConflict || body.Error == "" {
+		t.Errorf("ending a round with none running = %d %+v, want 409 and an error", w.Code, body)
+	}
+	// A countdown, before the round's timer starts, isn't ended
+	ctx := in.startRound()
+	if w := serveTest(t, handler, "POST", "/api/round/end", "secret"); w.Code != http.StatusConflict || ctx.Err() != nil {
+		t.Errorf("ending a round during its countdown = %d, cancelled %v; want 409, not cancelled", w.Code, ctx.Err() != nil)
+	}
+	round := g.NextRound()
+	g.live = liveRound{&round, newRoundTimer(time.Minute, g.Clock.Now())}
+	if w := serveTest(t, handler, "POST", "/api/round/start", "secret"); w.Code != http.StatusConflict {
+		t.Errorf("starting a round while one runs = %d, want 409", w.Code)
+	}
+	if w := serveTest(t, handler, "POST", "/api/round/end", "secret"); w.Code != http.StatusOK {
+		t.Errorf("ending the running round = %d, want 200", w.Code)
+	}
+	if ctx.Err() == nil {
+		t.Error("ending the round didn't cancel it")
+	}
+}
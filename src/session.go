@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+)
+
+// SESSION_VERSION is bumped whenever Session changes shape, so old files are
+// refused instead of half-loaded.
+const SESSION_VERSION = 2
+
+// Session is everything needed to pick a game back up between rounds with
+// the same shuffles still to come.
+type Session struct {
+	Version     int         `json:"version"`
+	Seed        int64       `json:"seed"`
+	Draws       uint64      `json:"draws"` // random numbers used from the seed so far
+	Played      int         `json:"played"`
+	Letters     string      `json:"letters"` // the previous round's letters
+	LastPrompts []string    `json:"last_prompts"`
+	LetterPool  string      `json:"letter_pool"`
+	PromptPool  []string    `json:"prompt_pool"`
+	Board       *Scoreboard `json:"board"`
+}
+
+// countingSource is a math/rand source that counts its draws, so a restored
+// game can fast-forward a fresh source from the same seed to where it was.
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// Session snapshots the game between rounds.
+func (g *Game) Session() Session {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := Session{
+		Version:     SESSION_VERSION,
+		Seed:        g.Seed,
+		Draws:       g.source.draws,
+		Played:      g.Played,
+		LastPrompts: []string{},
+		LetterPool:  string(g.letterPool),
+		PromptPool:  g.promptPool,
+		Board:       g.Board,
+	}
+	s.Letters = string(g.letters)
+	if g.last != nil {
+		s.LastPrompts = g.last.Prompts
+	}
+	return s
+}
+
+// SaveSession writes the session to path, atomically so a crash mid-write
+// can't leave it half written.
+func (g *Game) SaveSession(path string) error {
+	data, err := json.MarshalIndent(g.Session(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// writeFileAtomic writes data to path by way of a temporary file beside it,
+// so a crash mid-write leaves either the old file or the new one, never half
+// of one.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// loadSession reads a session saved at path, refusing one saved by a
+// different version of the game.
+func loadSession(path string) (Session, error) {
+	var s Session
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s isn't a saved session: %w", path, err)
+	}
+	if s.Version != SESSION_VERSION {
+		return s, fmt.Errorf("%s was saved as session version %d, but this version of the game reads version %d", path, s.Version, SESSION_VERSION)
+	}
+	return s, nil
+}
+
+// Restore puts the game back where s left it. It refuses a session whose
+// pools hold letters or prompts the game wasn't started with.
+func (g *Game) Restore(s Session) error {
+	for _, letter := range s.LetterPool + s.Letters {
+		if !slices.Contains(g.Letters, letter) {
+			return fmt.Errorf("the session uses letter %s, which isn't in -letters", string(letter))
+		}
+	}
+	for _, prompt := range append(slices.Clone(s.PromptPool), s.LastPrompts...) {
+		if !slices.Contains(g.prompts, prompt) {
+			return fmt.Errorf("the session uses prompt %q, which wasn't loaded", prompt)
+		}
+	}
+	g.restore(s)
+	return nil
+}
+
+// restore puts the game back where s left it, without checking s first.
+func (g *Game) restore(s Session) {
+	g.Seed = s.Seed
+	g.source = newCountingSource(s.Seed)
+	g.rng = rand.New(g.source)
+	for g.source.draws < s.Draws {
+		g.source.Uint64()
+	}
+	g.Played = s.Played
+	g.letters = []rune(s.Letters)
+	g.last = nil
+	if len(s.LastPrompts) > 0 {
+		g.last = &Round{Letters: g.letters, Prompts: s.LastPrompts}
+	}
+	g.letterPool = []rune(s.LetterPool)
+	g.promptPool = s.PromptPool
+	if s.Board != nil {
+		if s.Board.Totals == nil {
+			s.Board.Totals = map[string]int{}
+		}
+		g.mu.Lock()
+		g.Board = s.Board
+		g.mu.Unlock()
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	config := Config{NumPrompts: 2, Letters: []rune("ABCDE")}
+	g := newTestGame(config, TEST_PROMPTS)
+	for i := 0; i < 3; i++ {
+		g.NextRound()
+	}
+	g.Board.AddRound(map[string]int{"Al": 2, "Bo": 1})
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := g.SaveSession(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("saving left its temporary file behind: %v", err)
+	}
+
+	s, err := loadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := NewGame(config, TEST_PROMPTS, 99, nil, g.out)
+	if err := resumed.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Played != 3 || resumed.Seed != g.Seed || resumed.Board.Totals["Al"] != 2 || resumed.Board.Rounds != 1 {
+		t.Errorf("resumed at round %d with seed %d and standings %+v", resumed.Played, resumed.Seed, resumed.Board)
+	}
+	for i := 0; i < 10; i++ {
+		want, got := g.NextRound(), resumed.NextRound()
+		if got.Number != want.Number || !slices.Equal(got.Letters, want.Letters) || !slices.Equal(got.Prompts, want.Prompts) {
+			t.Fatalf("resumed game drew round %d %q %v, want round %d %q %v", got.Number, string(got.Letters), got.Prompts, want.Number, string(want.Letters), want.Prompts)
+		}
+	}
+}
+
+func TestLoadSessionErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, test := range []struct {
+		name, contents, says string
+	}{
+		{"not JSON", "round 3", "isn't a saved session"},
+		{"newer version", `{"version": 9, "seed": 1}`, "session version 9"},
+		{"old version", `{"version": 1, "seed": 1}`, "session version 1"},
+		{"no version", `{"seed": 1}`, "session version 0"},
+	} {
+		path := filepath.Join(dir, strings.ReplaceAll(test.name, " ", "-")+".json")
+		if err := os.WriteFile(path, []byte(test.contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadSession(path); err == nil || !strings.Contains(err.Error(), test.says) {
+			t.Errorf("%s: loadSession = %v, want an error saying %q", test.name, err, test.says)
+		}
+	}
+	if _, err := loadSession(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("loading a missing session succeeded")
+	}
+}
+
+func TestRestoreRefusesAnotherGame(t *testing.T) {
+	g := newTestGame(Config{NumPrompts: 2, Letters: []rune("ABC")}, TEST_PROMPTS)
+	for _, s := range []Session{
+		{Version: SESSION_VERSION, LetterPool: "AZ"},
+		{Version: SESSION_VERSION, Letters: "Q"},
+		{Version: SESSION_VERSION, PromptPool: []string{"Animals", "Zoos"}},
+		{Version: SESSION_VERSION, LastPrompts: []string{"Zoos"}},
+	} {
+		if err := g.Restore(s); err == nil {
+			t.Errorf("restored %+v into a game it isn't from", s)
+		}
+	}
+	if g.Played != 0 || len(g.RemainingLetters()) != 3 {
+		t.Error("a refused session changed the game")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// collectSheets has each player in turn paste their whole answer sheet, one
+// numbered answer per line ending with a blank line, all against a shared
+// deadline of window from when collection starts. Sheets not finished by
+// the deadline count only the answers given so far. It reports false if
+// input ran out.
+func collectSheets(w io.Writer, clock Clock, round *Round, players []string, lines <-chan string, window time.Duration) bool {
+	deadline := clock.After(window)
+	late := false
+	for _, player := range players {
+		block := []string{}
+		if !late {
+			fmt.Fprintf(w, msg("sheet-paste")+"\n", player, forLetters(round.Letters))
+			var ok bool
+			if block, late, ok = readBlock(lines, deadline); !ok {
+				return false
+			}
+			if late {
+				fmt.Fprintln(w, msg("sheets-up"))
+			}
+		}
+		answers, problems := parseSheet(block, len(round.Prompts))
+		for _, problem := range problems {
+			fmt.Fprintf(w, "\t(%s: %s)\n", player, problem)
+		}
+		for i := range round.Prompts {
+			answer := newAnswer(round, player, i, answers[i])
+			if !answer.Valid && answer.Text != "" {
+				fmt.Fprintf(w, "\t"+msg("invalid")+"\n", fmt.Sprintf("%s, %d. %s: ", player, i+1, answer.Text), answer.Reason)
+			}
+			round.Answers = append(round.Answers, answer)
+		}
+	}
+	return true
+}
+
+// readBlock reads lines up to a blank one, or until deadline fires, in which
+// case it reports late. It reports false if input ran out.
+func readBlock(lines <-chan string, deadline <-chan time.Time) (block []string, late, ok bool) {
+	block = []string{}
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return block, false, false
+			}
+			if strings.TrimSpace(line) == "" {
+				return block, false, true
+			}
+			block = append(block, line)
+		case <-deadline:
+			return block, true, true
+		}
+	}
+}
+
+// parseSheet reads answers written as "N. answer" lines (or "N) answer" or
+// "N: answer") into a map from prompt index to answer, for a round of n
+// prompts. A number with nothing after it, such as "3." or "3", is a blank
+// answer to that prompt. Lines that aren't numbered, number a prompt the
+// round doesn't have, or repeat a number are skipped and described in
+// problems, rather than losing the rest of the sheet.
+func parseSheet(lines []string, n int) (answers map[int]string, problems []string) {
+	answers, problems = map[int]string{}, []string{}
+	seen := map[int]bool{} // numbers already given, blank answers included
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		end := strings.IndexFunc(line, func(r rune) bool { return r < '0' || r > '9' })
+		if end < 0 {
+			end = len(line) // just a number, so a blank answer
+		}
+		if end == 0 {
+			problems = append(problems, fmt.Sprintf(msg("no-number"), i+1, line))
+			continue
+		}
+		number, _ := strconv.Atoi(line[:end])
+		text := strings.TrimSpace(strings.TrimLeft(line[end:], ".):"))
+		switch {
+		case number < 1 || number > n:
+			problems = append(problems, fmt.Sprintf(msg("no-prompt"), i+1, number))
+		case seen[number]:
+			problems = append(problems, fmt.Sprintf(msg("answered"), i+1, number))
+		default:
+			seen[number] = true
+			answers[number-1] = text
+		}
+	}
+	return answers, problems
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSheet(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		lines    []string
+		answers  map[int]string
+		problems []string
+	}{
+		{"well formed", []string{"1. Bear", "2. Banana", "3. Boston"}, map[int]string{0: "Bear", 1: "Banana", 2: "Boston"}, nil},
+		{"other numbering", []string{"1) Bear", "2: Banana", "3 Boston"}, map[int]string{0: "Bear", 1: "Banana", 2: "Boston"}, nil},
+		{"out of order", []string{"3. Boston", "1. Bear"}, map[int]string{0: "Bear", 2: "Boston"}, nil},
+		{"untidy spacing", []string{"   1.    Big bear  ", "2.Banana"}, map[int]string{0: "Big bear", 1: "Banana"}, nil},
+		{"blank answers", []string{"1. Bear", "2.", " 3 "}, map[int]string{0: "Bear", 1: "", 2: ""}, nil},
+		{"not numbered", []string{"1. Bear", "Banana", "3. Boston"}, map[int]string{0: "Bear", 2: "Boston"},
+			[]string{fmt.Sprintf(msg("no-number"), 2, "Banana")}},
+		{"no such prompt", []string{"0. Bear", "4. Banana", "2. Bagel"}, map[int]string{1: "Bagel"},
+			[]string{fmt.Sprintf(msg("no-prompt"), 1, 0), fmt.Sprintf(msg("no-prompt"), 2, 4)}},
+		{"answered twice", []string{"1. Bear", "1. Bison"}, map[int]string{0: "Bear"},
+			[]string{fmt.Sprintf(msg("answered"), 2, 1)}},
+		{"left blank, then answered", []string{"2.", "2. Banana", "3. Boston", "3"}, map[int]string{1: "", 2: "Boston"},
+			[]string{fmt.Sprintf(msg("answered"), 2, 2), fmt.Sprintf(msg("answered"), 4, 3)}},
+		{"all messy", []string{"", "- Bear", "1."}, map[int]string{0: ""},
+			[]string{fmt.Sprintf(msg("no-number"), 1, ""), fmt.Sprintf(msg("no-number"), 2, "- Bear")}},
+	} {
+		answers, problems := parseSheet(test.lines, 3)
+		if !maps.Equal(answers, test.answers) {
+			t.Errorf("%s: answers %q, want %q", test.name, answers, test.answers)
+		}
+		if strings.Join(problems, "\n") != strings.Join(test.problems, "\n") {
+			t.Errorf("%s: problems %q, want %q", test.name, problems, test.problems)
+		}
+	}
+}
+
+func TestCollectSheets(t *testing.T) {
+	round := Round{Number: 1, Letters: []rune("B"), Prompts: []string{"Animals", "Fruits"}}
+	lines := make(chan string)
+	clock := newFakeClock()
+	done := make(chan bool, 1)
+	go func() {
+		done <- collectSheets(io.Discard, clock, &round, []string{"Al", "Bo", "Cy"}, lines, time.Minute)
+	}()
+	clock.BlockUntil(t, 1)
+	for _, line := range []string{"1. Bear", "two. Banana", "2. apple", "", "2. Blueberry"} {
+		lines <- line
+	}
+	// Bo's sheet isn't finished when time's up, and Cy never starts one
+	clock.Advance(time.Minute)
+	if !<-done {
+		t.Fatal("input ran out")
+	}
+	got := []string{}
+	for _, answer := range round.Answers {
+		got = append(got, fmt.Sprintf("%s %d %q %v", answer.Player, answer.Prompt+1, answer.Text, answer.Valid))
+	}
+	want := []string{
+		`Al 1 "Bear" true`, `Al 2 "apple" false`,
+		`Bo 1 "" false`, `Bo 2 "Blueberry" true`,
+		`Cy 1 "" false`, `Cy 2 "" false`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("collected\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCollectSheetsInputRunsOut(t *testing.T) {
+	round := Round{Number: 1, Letters: []rune("B"), Prompts: []string{"Animals"}}
+	lines := make(chan string, 1)
+	lines <- "1. Bear"
+	close(lines)
+	if collectSheets(io.Discard, newFakeClock(), &round, []string{"Al"}, lines, time.Minute) {
+		t.Error("reported a full sheet when input ran out")
+	}
+}
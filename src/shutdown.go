@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// shutdown is the one way out of a game, whether it ends by itself, the
+// players quit or a signal stops it: it saves the final standings, then
+// closes everything opened for the game, newest first. It runs once, however
+// many paths reach it.
+type shutdown struct {
+	once    sync.Once
+	game    *Game // whose standings to save to STANDINGS_PATH, if set and there are PLAYERS
+	closers []namedCloser
+	code    int
+}
+
+// saveStandings writes the standings to STANDINGS_PATH, from a copy taken
+// under the lock.
+func (g *Game) saveStandings() error {
+	_, board := g.final()
+	return board.Save(STANDINGS_PATH)
+}
+
+type namedCloser struct {
+	name string
+	io.Closer
+}
+
+// add registers c, described by name in any error, to be closed on the way
+// out.
+func (s *shutdown) add(name string, c io.Closer) {
+	s.closers = append(s.closers, namedCloser{name, c})
+}
+
+// run shuts down and returns the exit code: 0 if everything was saved and
+// closed, 1 if anything failed, which is logged. If a round is being written
+// out it waits for that to finish, and then keeps the game from starting on
+// another, since the files are closed and the program is about to exit.
+// Calls after the first wait for it to finish and return the same code.
+func (s *shutdown) run() int {
+	s.once.Do(func() {
+		if s.game != nil {
+			s.game.saving.Lock()
+		}
+		if STANDINGS_PATH != "" && len(PLAYERS) > 0 && s.game != nil {
+			if err := s.game.saveStandings(); err != nil {
+				slog.Error("saving standings", "path", STANDINGS_PATH, "err", err)
+				s.code = 1
+			}
+		}
+		for i := len(s.closers) - 1; i >= 0; i-- {
+			if err := s.closers[i].Close(); err != nil {
+				slog.Error("closing "+s.closers[i].name, "err", err)
+				s.code = 1
+			}
+		}
+	})
+	return s.code
+}
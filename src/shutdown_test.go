@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestShutdownWaitsForRoundWrite interrupts a game while a round is being
+// written out, as the signal handler would, and checks the shutdown waits
+// for the write and leaves every file complete.
+func TestShutdownWaitsForRoundWrite(t *testing.T) {
+	dir := t.TempDir()
+	players, standings := PLAYERS, STANDINGS_PATH
+	t.Cleanup(func() { PLAYERS, STANDINGS_PATH = players, standings })
+	PLAYERS = []string{"Al", "Bo"}
+	STANDINGS_PATH = filepath.Join(dir, "standings.json")
+
+	g := NewGame(Config{NumPrompts: 2, Letters: []rune("B"), LettersPer: 1}, []string{"Birds", "Bands"}, 1, nil, io.Discard)
+	stop := &shutdown{game: g}
+	var err error
+	if g.Exporter, err = newCSVExporter(filepath.Join(dir, "answers.csv"), sessionInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	stop.add("export file", g.Exporter)
+	if g.History, err = openHistoryLog(filepath.Join(dir, "history.jsonl"), sessionInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	stop.add("history file", g.History)
+
+	round := g.NextRound()
+	for _, player := range PLAYERS {
+		for i := range round.Prompts {
+			round.Answers = append(round.Answers, newAnswer(&round, player, i, "b"+player))
+		}
+	}
+	points := standardScorer{SCORING}.Score(&round)
+
+	// The round is being written when the interrupt comes
+	g.saving.Lock()
+	done := make(chan int)
+	go func() { done <- stop.run() }()
+	select {
+	case <-done:
+		t.Fatal("shutdown didn't wait for the round being written")
+	case <-time.After(50 * time.Millisecond):
+	}
+	g.mu.Lock()
+	g.Board.AddRound(points)
+	g.mu.Unlock()
+	if err := g.Exporter.WriteRound(round); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.History.WriteRound(round, points, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	g.saving.Unlock()
+	if code := <-done; code != 0 {
+		t.Fatalf("shutdown exited %d, want 0", code)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "answers.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 1 + len(round.Answers); len(rows) != want {
+		t.Errorf("export has %d rows, want %d", len(rows), want)
+	}
+
+	file, err = os.Open(filepath.Join(dir, "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	lines := 0
+	for ; scanner.Scan(); lines++ {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("history line %d: %v", lines+1, err)
+		}
+		if len(entry.Answers) != len(round.Answers) {
+			t.Errorf("history has %d answers, want %d", len(entry.Answers), len(round.Answers))
+		}
+	}
+	if lines != 1 {
+		t.Errorf("history has %d lines, want 1", lines)
+	}
+
+	board := loadScoreboard(STANDINGS_PATH)
+	if board.Rounds != 1 || board.Totals["Al"] != 2 || board.Totals["Bo"] != 2 {
+		t.Errorf("standings = %+v, want a round with 2 points each", board)
+	}
+}
+
+// TestStopSignalLeavesCompleteFiles plays a round in a separate process,
+// stops it with a signal at the menu and checks everything it wrote was
+// flushed.
+func TestStopSignalLeavesCompleteFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("can't send a stop signal on this platform")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-no-wait", "-seed", "7", "-players", "Al,Bo", "-prompts", "2",
+		"-export", filepath.Join(dir, "answers.csv"), "-history", filepath.Join(dir, "history.jsonl"), "-standings", filepath.Join(dir, "standings.json"))
+	cmd.Env = append(os.Environ(), RUN_GAME_ENV+"=1", "HOME="+dir)
+	input, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out lockedBuffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill() })
+	says := func(s string, n int) func() bool {
+		return func() bool { return strings.Count(out.String(), s) >= n }
+	}
+
+	io.WriteString(input, "1\n")
+	eventually(t, says(msg("times-up"), 1))
+	io.WriteString(input, "Bear\nBanana\nBison\n\n")
+	eventually(t, says(msg("menu"), 2))
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	var exit *exec.ExitError
+	if err := cmd.Wait(); errors.As(err, &exit) {
+		t.Fatalf("exited %d:\n%s", exit.ExitCode(), out.String())
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), msg("bye")) || !strings.Contains(out.String(), "Rounds played: 1") {
+		t.Errorf("didn't say goodbye and report the round:\n%s", out.String())
+	}
+
+	file, err := os.Open(filepath.Join(dir, "answers.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if rows, err := csv.NewReader(file).ReadAll(); err != nil || len(rows) != 5 {
+		t.Errorf("export has %d rows (%v), want a header and four answers", len(rows), err)
+	}
+	history, err := loadHistory(filepath.Join(dir, "history.jsonl"))
+	if err != nil || len(history) != 1 {
+		t.Errorf("history has %d rounds (%v), want 1", len(history), err)
+	}
+	board := loadScoreboard(filepath.Join(dir, "standings.json"))
+	if board.Rounds != 1 || len(board.Totals) != 2 {
+		t.Errorf("standings = %+v, want a round for Al and Bo", board)
+	}
+}
+
+func TestShutdownRunsOnceAndReportsFailure(t *testing.T) {
+	closed := 0
+	stop := &shutdown{}
+	stop.add("counter", closerFunc(func() error { closed++; return nil }))
+	stop.add("broken", closerFunc(func() error { return errors.New("disk full") }))
+	if code := stop.run(); code != 1 {
+		t.Errorf("run = %d, want 1 when closing fails", code)
+	}
+	if code := stop.run(); code != 1 || closed != 1 {
+		t.Errorf("second run = %d with %d closes, want 1 and 1", code, closed)
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
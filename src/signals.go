@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Extend adds d to the running round's timer, reporting false if no timer is
+// running.
+func (g *Game) Extend(d time.Duration) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.live.timer == nil {
+		return false
+	}
+	g.live.timer.Extend(d)
+	return true
+}
+
+// listenControl handles EXTEND_SIGNAL and END_SIGNAL from sigs until the
+// channel is closed: the first adds EXTEND_BY to the running round, like
+// EXTEND_KEY, and the second ends it early, like SKIP_KEY.
+func (g *Game) listenControl(sigs <-chan os.Signal, in *interrupter) {
+	for sig := range sigs {
+		switch sig {
+		case EXTEND_SIGNAL:
+			if g.Extend(EXTEND_BY) {
+				slog.Info("round extended by signal", "by", EXTEND_BY)
+			} else {
+				slog.Warn("no round to extend", "signal", sig)
+			}
+		case END_SIGNAL:
+			if in.cancelRound() {
+				slog.Info("round ended by signal")
+			} else {
+				slog.Warn("no round to end", "signal", sig)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// Windows and the like have no SIGUSR1 or SIGUSR2, so rounds can't be
+// extended or ended by signal there.
+var (
+	EXTEND_SIGNAL os.Signal = nil
+	END_SIGNAL    os.Signal = nil
+)
+
+// STOP_SIGNALS quit the game at once, shutting it down as cleanly as
+// quitting does. Closing the console window arrives as SIGTERM.
+var STOP_SIGNALS = []os.Signal{syscall.SIGTERM}
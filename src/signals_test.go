@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// playSignalled starts a round of length on a fake clock with control
+// signals coming from the returned channel, reporting how it ended on done.
+func playSignalled(t *testing.T, length time.Duration) (*Game, *fakeClock, chan<- os.Signal, <-chan countdownResult) {
+	t.Helper()
+	if EXTEND_SIGNAL == nil {
+		t.Skip("no control signals on this platform")
+	}
+	set(t, &QUIET, true)
+	set(t, &BELLS, 0)
+	set(t, &EXTEND_BY, 30*time.Second)
+	g := NewGame(Config{Duration: length, NumPrompts: 1, Letters: []rune("A"), Resolution: time.Second}, TEST_PROMPTS, 1, nil, io.Discard)
+	clock := newFakeClock()
+	g.Clock = clock
+	in := &interrupter{out: io.Discard}
+	sigs, listened := make(chan os.Signal), make(chan bool)
+	go func() {
+		g.listenControl(sigs, in)
+		close(listened)
+	}()
+	t.Cleanup(func() {
+		close(sigs)
+		<-listened
+	})
+	done := make(chan countdownResult, 1)
+	go func() {
+		round := g.NextRound()
+		result := g.Play(&round, in)
+		done <- countdownResult{result, g.Stats.TimePlayed}
+	}()
+	clock.BlockUntil(t, 1)
+	return g, clock, sigs, done
+}
+
+func TestExtendSignal(t *testing.T) {
+	g, clock, sigs, done := playSignalled(t, 10*time.Second)
+	sigs <- EXTEND_SIGNAL
+	eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.live.timer.Total() == 40*time.Second
+	})
+	for i := 0; i < 40; i++ {
+		clock.BlockUntil(t, 1)
+		select {
+		case got := <-done:
+			t.Fatalf("round ended (%s) %ds in, want it extended to 40s", got.result, i)
+		default:
+		}
+		clock.Advance(time.Second)
+	}
+	if got := <-done; got.result != TIME_UP || got.elapsed != 40*time.Second {
+		t.Errorf("round = %s after %s, want time up after 40s", got.result, got.elapsed)
+	}
+	if g.Extend(time.Second) {
+		t.Error("extended a round that had finished")
+	}
+}
+
+func TestEndSignal(t *testing.T) {
+	_, clock, sigs, done := playSignalled(t, time.Minute)
+	clock.Advance(5 * time.Second)
+	clock.BlockUntil(t, 1)
+	sigs <- END_SIGNAL
+	if got := <-done; got.result != ENDED_EARLY || got.elapsed != 5*time.Second {
+		t.Errorf("round = %s after %s, want it ended early after 5s", got.result, got.elapsed)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals that extend and end the running round, for when there's no
+// keyboard to do it with.
+var (
+	EXTEND_SIGNAL os.Signal = syscall.SIGUSR1
+	END_SIGNAL    os.Signal = syscall.SIGUSR2
+)
+
+// STOP_SIGNALS quit the game at once, shutting it down as cleanly as
+// quitting does, unlike an interrupt, which first ends the round.
+var STOP_SIGNALS = []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
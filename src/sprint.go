@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// PlaySprint plays round as a category sprint: rather than one clock for
+// the whole list, each prompt is shown in turn with SPRINT_TIME of its own,
+// moving on when that runs out or SKIP_KEY is entered. With PLAYERS, each
+// prompt's answers are collected as soon as its clock stops. It returns
+// TIME_UP once every prompt is done, ENDED_EARLY if the round was ended, as
+// by an interrupt, or how else it was abandoned.
+func (g *Game) PlaySprint(round *Round, in *interrupter) timerResult {
+	fmt.Fprintln(g.out, SEP)
+	printLetters(g.out, round.Letters)
+	fmt.Fprintf(g.out, msg("sprint")+"\n", formatClock(SPRINT_TIME))
+	printControls(g.out, "sprint-keys")
+
+	result, elapsed := g.sprint(round, in)
+	g.mu.Lock()
+	g.live.timer = nil
+	g.Stats.record(*round, result, elapsed)
+	g.mu.Unlock()
+	switch result {
+	case TIME_UP:
+		fmt.Fprintln(g.out, msg("every-prompt"))
+	case ENDED_EARLY:
+		fmt.Fprintln(g.out, msg("ended-early"))
+	case REDRAWN:
+		fmt.Fprintln(g.out, msg("redrawing"))
+	case UNDONE:
+		fmt.Fprintln(g.out, msg("undone"))
+	case QUIT:
+		fmt.Fprintln(g.out, msg("bye"))
+	}
+	return result
+}
+
+// sprint runs round's prompts one after another, each on a fresh round timer
+// of SPRINT_TIME on the game's clock, collecting answers to each as its
+// timer stops if there are PLAYERS. SKIP_KEY only moves on to the next
+// prompt, but ending the round, as an interrupt does, ends the sprint with
+// ENDED_EARLY. Taking the draw back is only allowed on the first prompt,
+// while the sprint is still inside UNDO_WINDOW. It returns how the sprint
+// ended and the time the timers ran between them.
+func (g *Game) sprint(round *Round, in *interrupter) (timerResult, time.Duration) {
+	w := g.out
+	total := time.Duration(0)
+	for i, prompt := range round.Prompts {
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, msg("sprint-prompt")+"\n", i+1, len(round.Prompts))
+		fmt.Fprintf(w, PROMPT_FORMAT+"\n", painted{COLOR_DIM, i + 1}, prompt)
+
+		var view timerView = &lineView{}
+		if QUIET {
+			view = quietView{}
+		}
+		timer := newRoundTimer(SPRINT_TIME, g.Clock.Now())
+		if g.Events != nil {
+			view = &emitView{timerView: view, events: g.Events, round: round.Number, timer: timer, clock: g.Clock, last: -1}
+		}
+		g.mu.Lock()
+		g.live = liveRound{round, timer}
+		g.mu.Unlock()
+		ctx := in.startRound()
+		result, elapsed := countdown(ctx, w, view, g.Clock, timer, g.Resolution, g.lines, nil)
+		for result == UNDONE && i > 0 {
+			view.Note(w, fmt.Sprintf(msg("undo-late"), UNDO_WINDOW))
+			result, elapsed = countdown(ctx, w, view, g.Clock, timer, g.Resolution, g.lines, nil)
+		}
+		interrupted := ctx.Err() != nil // rather than skipped with SKIP_KEY
+		in.endRound()
+		total += elapsed
+		switch {
+		case result == TIME_UP:
+			fmt.Fprintln(w, paint(COLOR_RED, msg("sprint-up")))
+			ring(w, BELLS)
+		case result == ENDED_EARLY && !interrupted:
+		default:
+			return result, total
+		}
+		if len(PLAYERS) > 0 && !collectPrompt(w, round, PLAYERS, i, g.lines) {
+			return QUIT, total
+		}
+	}
+	return TIME_UP, total
+}
+
+// collectPrompt asks each player in turn for their answer to one of round's
+// prompts. It reports false if input ran out.
+func collectPrompt(w io.Writer, round *Round, players []string, prompt int, lines <-chan string) bool {
+	fmt.Fprintf(w, msg("sprint-answers")+"\n", prompt+1, round.Prompts[prompt], forLetters(round.Letters))
+	for _, player := range players {
+		fmt.Fprintf(w, "  %s: ", player)
+		if !answerPrompt(w, round, player, prompt, lines) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startSprint plays round as a sprint on a manual clock in the background,
+// reading input, and sends how it ended on the returned channel.
+func startSprint(t *testing.T, round *Round, input chan string) (*Game, *fakeClock, *interrupter, *bytes.Buffer, <-chan timerResult) {
+	t.Helper()
+	set(t, &QUIET, true)
+	set(t, &BELLS, 0)
+	set(t, &SPRINT_TIME, 5*time.Second)
+	var out bytes.Buffer
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: len(round.Prompts), Letters: round.Letters, Resolution: time.Second}, TEST_PROMPTS, 1, input, &out)
+	clock := newFakeClock()
+	g.Clock = clock
+	in := &interrupter{out: &out}
+	done := make(chan timerResult, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		done <- g.PlaySprint(round, in)
+	}()
+	t.Cleanup(func() { <-finished })
+	return g, clock, in, &out, done
+}
+
+// runDown advances clock a second at a time for d, as the sprint waits on it.
+func runDown(t *testing.T, clock *fakeClock, d time.Duration) {
+	t.Helper()
+	for range int(d / time.Second) {
+		clock.BlockUntil(t, 1)
+		clock.Advance(time.Second)
+	}
+}
+
+func TestSprintTimesEachPrompt(t *testing.T) {
+	round := Round{Number: 1, Letters: []rune("B"), Prompts: []string{"Animals", "Fruits", "Rivers"}}
+	input := make(chan string)
+	g, clock, _, out, done := startSprint(t, &round, input)
+	start := clock.Now()
+
+	// The first prompt runs its full 5s and the sprint moves on by itself
+	runDown(t, clock, 5*time.Second)
+	// The second is skipped 2s in
+	runDown(t, clock, 2*time.Second)
+	clock.BlockUntil(t, 1)
+	input <- SKIP_KEY
+	// And the last runs out too, once it's waiting alongside the skipped
+	// prompt's last tick
+	clock.BlockUntil(t, 2)
+	clock.Advance(time.Second)
+	runDown(t, clock, 4*time.Second)
+	if result := <-done; result != TIME_UP {
+		t.Fatalf("sprint = %s, want time up once every prompt was done", result)
+	}
+	if ran := clock.Now().Sub(start); ran != 12*time.Second {
+		t.Errorf("sprint ran %s on the clock, want 12s", ran)
+	}
+	if g.Stats.TimePlayed != 12*time.Second || g.Stats.Rounds != 1 {
+		t.Errorf("stats %+v, want one round of 12s", g.Stats)
+	}
+	if n := strings.Count(out.String(), msg("sprint-up")); n != 2 {
+		t.Errorf("said time's up %d times, want once each for the two prompts that ran out:\n%s", n, out.String())
+	}
+	// Each prompt is shown once, in turn, numbered out of the three
+	last := -1
+	for i, prompt := range round.Prompts {
+		header := strings.Index(out.String(), fmt.Sprintf(msg("sprint-prompt"), i+1, 3))
+		shown := strings.Index(out.String(), prompt)
+		if header < 0 || shown < header || header < last || strings.Count(out.String(), prompt) != 1 {
+			t.Errorf("prompt %d, %s, isn't shown once after the one before it:\n%s", i+1, prompt, out.String())
+		}
+		last = shown
+	}
+	if !strings.HasSuffix(out.String(), msg("every-prompt")+"\n") {
+		t.Errorf("sprint doesn't end by saying every prompt is done:\n%s", out.String())
+	}
+}
+
+func TestSprintCollectsEachPromptsAnswers(t *testing.T) {
+	set(t, &PLAYERS, []string{"Al", "Bo"})
+	round := Round{Number: 1, Letters: []rune("B"), Prompts: []string{"Animals", "Fruits", "Rivers"}}
+	input := make(chan string)
+	_, clock, in, out, done := startSprint(t, &round, input)
+
+	// The first prompt's answers are asked for once its clock runs out
+	runDown(t, clock, 5*time.Second)
+	input <- "Bear"
+	input <- "Bison"
+	// The second's as soon as it's skipped, with no time up
+	clock.BlockUntil(t, 1)
+	input <- SKIP_KEY
+	input <- "Banana"
+	input <- ""
+	// And an interrupt during the third ends the whole sprint unanswered
+	clock.BlockUntil(t, 2)
+	in.mu.Lock()
+	in.cancel()
+	in.mu.Unlock()
+	if result := <-done; result != ENDED_EARLY {
+		t.Fatalf("interrupted sprint = %s, want it ended early", result)
+	}
+	got := []string{}
+	for _, answer := range round.Answers {
+		got = append(got, answer.Player+" "+round.Prompts[answer.Prompt]+" "+answer.Text)
+	}
+	want := []string{"Al Animals Bear", "Bo Animals Bison", "Al Fruits Banana", "Bo Fruits "}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("collected\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if n := strings.Count(out.String(), msg("sprint-up")); n != 1 {
+		t.Errorf("said time's up %d times, want only for the first prompt:\n%s", n, out.String())
+	}
+	if ended := strings.LastIndex(out.String(), msg("ended-early")); ended < strings.Index(out.String(), "Rivers") {
+		t.Errorf("sprint doesn't say it ended early after showing the last prompt:\n%s", out.String())
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Stats tallies a game's rounds for the end-of-game report.
+type Stats struct {
+	Rounds     int           // rounds played, whether or not time ran out
+	EndedEarly int           // rounds ended before time ran out
+	Redrawn    int           // draws abandoned for a fresh one
+	Letters    []string      // each played round's letters, in order
+	TimePlayed time.Duration // time on the clock across rounds, excluding pauses
+
+	LetterShuffles int // times the letter pool ran out and was reshuffled
+	PromptShuffles int // times the prompt pool did
+}
+
+// record tallies a round that stopped with result after elapsed on the clock.
+func (s *Stats) record(round Round, result timerResult, elapsed time.Duration) {
+	if result == UNDONE {
+		return
+	}
+	s.TimePlayed += elapsed
+	if round.LettersShuffled {
+		s.LetterShuffles++
+	}
+	if round.PromptsShuffled {
+		s.PromptShuffles++
+	}
+	switch result {
+	case REDRAWN:
+		s.Redrawn++
+		return
+	case ENDED_EARLY, QUIT:
+		s.EndedEarly++
+	}
+	s.Rounds++
+	if len(round.Letters) > 0 {
+		s.Letters = append(s.Letters, letterText(round.Letters))
+	}
+}
+
+// announceShuffle tells the players if drawing round used up a pool and
+// shuffled it back in, with one notice covering both pools if need be.
+func announceShuffle(w io.Writer, round Round) {
+	switch {
+	case round.LettersShuffled && round.PromptsShuffled:
+		fmt.Fprintln(w, msg("both-reset"))
+	case round.LettersShuffled:
+		fmt.Fprintln(w, msg("letters-reset"))
+	case round.PromptsShuffled:
+		fmt.Fprintln(w, msg("prompts-reset"))
+	}
+}
+
+// final copies the stats and standings, taking the lock only as long as
+// that takes, so they can be written out without holding it.
+func (g *Game) final() (Stats, *Scoreboard) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := g.Stats
+	s.Letters = slices.Clone(s.Letters)
+	return s, g.Board.clone()
+}
+
+// Report prints the end-of-game statistics, and the final standings if
+// anyone scored.
+func (g *Game) Report() {
+	s, board := g.final()
+	letters := []string{}
+	for _, letter := range s.Letters {
+		letters = append(letters, string(letter))
+	}
+	fmt.Fprintln(g.out, SEP)
+	fmt.Fprintln(g.out, msg("game-over"))
+	fmt.Fprintf(g.out, "  "+msg("stat-rounds")+"\n", s.Rounds, s.EndedEarly, s.Redrawn)
+	if len(letters) > 0 {
+		fmt.Fprintf(g.out, "  "+msg("stat-letters")+"\n", strings.Join(letters, ", "))
+	}
+	if s.LetterShuffles > 0 || s.PromptShuffles > 0 {
+		fmt.Fprintf(g.out, "  "+msg("stat-shuffles")+"\n", s.LetterShuffles, s.PromptShuffles)
+	}
+	fmt.Fprintf(g.out, "  "+msg("stat-time")+"\n", formatClock(s.TimePlayed))
+	if len(board.Totals) > 0 {
+		board.Print(g.out)
+	}
+	fmt.Fprintln(g.out, SEP)
+}
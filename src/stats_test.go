@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsRecord(t *testing.T) {
+	var s Stats
+	for _, played := range []struct {
+		letters string
+		result  timerResult
+		elapsed time.Duration
+		flags   Round
+	}{
+		{"A", TIME_UP, 3 * time.Minute, Round{}},
+		{"B", REDRAWN, 5 * time.Second, Round{}},
+		{"C", ENDED_EARLY, time.Minute, Round{PromptsShuffled: true}},
+		{"D", UNDONE, 2 * time.Second, Round{}},
+		{"EF", TIME_UP, 3 * time.Minute, Round{LettersShuffled: true}},
+		{"", QUIT, 30 * time.Second, Round{}},
+	} {
+		round := played.flags
+		round.Letters = []rune(played.letters)
+		s.record(round, played.result, played.elapsed)
+	}
+	if s.Rounds != 4 || s.EndedEarly != 2 || s.Redrawn != 1 {
+		t.Errorf("%d rounds, %d ended early and %d redrawn; want 4, 2 and 1", s.Rounds, s.EndedEarly, s.Redrawn)
+	}
+	if want := []string{"A", "C", "E or F"}; !slices.Equal(s.Letters, want) {
+		t.Errorf("letters %q, want %q", s.Letters, want)
+	}
+	if want := 7*time.Minute + 35*time.Second; s.TimePlayed != want {
+		t.Errorf("played for %s, want %s", s.TimePlayed, want)
+	}
+	if s.LetterShuffles != 1 || s.PromptShuffles != 1 {
+		t.Errorf("%d letter and %d prompt reshuffles, want 1 of each", s.LetterShuffles, s.PromptShuffles)
+	}
+}
+
+func TestReport(t *testing.T) {
+	var out bytes.Buffer
+	g := NewGame(Config{}, TEST_PROMPTS, 1, nil, &out)
+	g.Stats = Stats{Rounds: 3, EndedEarly: 1, Redrawn: 2, Letters: []string{"A", "B", "S"}, TimePlayed: 7 * time.Minute}
+	g.Board.AddRound(map[string]int{"Al": 4, "Bo": 6})
+	g.Report()
+	report := out.String()
+	for _, want := range []string{
+		"Game over!\n",
+		"  Rounds played: 3 (1 ended early, 2 redrawn)\n",
+		"  Letters used: A, B, S\n",
+		"  Time played: 7m0s\n",
+		"Bo",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Index(report, "Bo") > strings.Index(report, "Al") {
+		t.Errorf("standings don't lead with Bo's 6 points:\n%s", report)
+	}
+	if strings.Contains(report, "Reshuffles") {
+		t.Errorf("report mentions reshuffles there weren't:\n%s", report)
+	}
+}
+
+func TestReshuffleCounts(t *testing.T) {
+	set(t, &QUIET, true)
+	set(t, &BELLS, 0)
+	// Three letters a round at a time, and four prompts two at a time, run out
+	// every third and every other round, and both at round 7
+	var out bytes.Buffer
+	g := NewGame(Config{Duration: time.Minute, NumPrompts: 2, Letters: []rune("ABC"), Resolution: time.Second, Rounds: 12}, TEST_PROMPTS[:4], 1, nil, &out)
+	clock := newFakeClock()
+	clock.auto = true
+	g.Clock = clock
+	g.Run(&interrupter{out: &out})
+	if g.Stats.LetterShuffles != 3 || g.Stats.PromptShuffles != 5 {
+		t.Errorf("%d letter and %d prompt reshuffles in 12 rounds, want 3 and 5", g.Stats.LetterShuffles, g.Stats.PromptShuffles)
+	}
+	for key, want := range map[string]int{"both-reset": 1, "letters-reset": 2, "prompts-reset": 4} {
+		if n := strings.Count(out.String(), msg(key)+"\n"); n != want {
+			t.Errorf("said %q %d times, want %d", msg(key), n, want)
+		}
+	}
+	if !strings.Contains(out.String(), fmt.Sprintf(msg("stat-shuffles"), 3, 5)) {
+		t.Errorf("report doesn't count the reshuffles:\n%s", out.String())
+	}
+}
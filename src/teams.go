@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Team is a named group of players whose points are pooled in the standings.
+type Team struct {
+	Name    string
+	Players []string
+}
+
+// TeamStanding is one row of the team standings, with its players' own
+// standings underneath.
+type TeamStanding struct {
+	Team    string
+	Points  int
+	Players []Standing
+}
+
+// parseTeams parses teams written as "Red:Alice,Bob;Blue:Carol,Dan". Every
+// team needs a name and at least one player, and nobody can play for two
+// teams.
+func parseTeams(s string) ([]Team, error) {
+	teams := []Team{}
+	seen := map[string]string{}
+	for _, item := range strings.Split(s, ";") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		name, players, ok := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return []Team{}, fmt.Errorf("%q should look like NAME:PLAYER,PLAYER", item)
+		}
+		team := Team{Name: name, Players: splitList(players)}
+		if len(team.Players) == 0 {
+			return []Team{}, fmt.Errorf("team %s has no players", name)
+		}
+		for _, player := range team.Players {
+			if other, ok := seen[player]; ok {
+				return []Team{}, fmt.Errorf("%s is on both %s and %s", player, other, name)
+			}
+			seen[player] = name
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+// teamPlayers lists every team's players, in team order.
+func teamPlayers(teams []Team) []string {
+	players := []string{}
+	for _, team := range teams {
+		players = append(players, team.Players...)
+	}
+	return players
+}
+
+// TeamStandings returns teams ordered by their players' combined points,
+// highest first, each with its players ordered the same way. Ties are broken
+// by TIEBREAK as Standings breaks them, a team's duplicates being its
+// players' put together.
+func (s *Scoreboard) TeamStandings(teams []Team) []TeamStanding {
+	standings := []TeamStanding{}
+	duplicates := map[string]int{}
+	for _, team := range teams {
+		standing := TeamStanding{Team: team.Name, Players: []Standing{}}
+		for _, player := range team.Players {
+			standing.Points += s.Totals[player]
+			standing.Players = append(standing.Players, Standing{player, s.Totals[player]})
+			duplicates[team.Name] += s.Duplicates[player]
+		}
+		sort.Slice(standing.Players, func(i, j int) bool {
+			return ranksAbove(standing.Players[i], standing.Players[j], s.Duplicates, s.Playoff)
+		})
+		standings = append(standings, standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		return ranksAbove(Standing{a.Team, a.Points}, Standing{b.Team, b.Points}, duplicates, nil)
+	})
+	return standings
+}
+
+// printTeams prints the team standings with each player's points indented
+// under their team.
+func (s *Scoreboard) printTeams(w io.Writer, teams []Team) {
+	fmt.Fprintf(w, msg("team-standings")+"\n", s.Rounds)
+	for i, team := range s.TeamStandings(teams) {
+		fmt.Fprintf(w, "  %d.\t%s\t%d\n", i+1, team.Team, team.Points)
+		for _, player := range team.Players {
+			fmt.Fprintf(w, "    \t  %s\t%d\n", player.Player, player.Points)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTeams(t *testing.T) {
+	teams, err := parseTeams(" Red : Alice, Bob ; Blue:Carol,Dan;")
+	want := []Team{{"Red", []string{"Alice", "Bob"}}, {"Blue", []string{"Carol", "Dan"}}}
+	if err != nil || !reflect.DeepEqual(teams, want) {
+		t.Errorf("parseTeams = %v, %v; want %v", teams, err, want)
+	}
+	if players := teamPlayers(teams); strings.Join(players, ",") != "Alice,Bob,Carol,Dan" {
+		t.Errorf("teamPlayers = %v, want Alice, Bob, Carol and Dan", players)
+	}
+	for _, bad := range []string{"Red", ":Alice", "Red:", "Red:Alice;Blue:Alice"} {
+		if teams, err := parseTeams(bad); err == nil {
+			t.Errorf("parseTeams(%q) = %v, want an error", bad, teams)
+		}
+	}
+}
+
+func TestTeamStandings(t *testing.T) {
+	teams := []Team{{"Red", []string{"Alice", "Bob"}}, {"Blue", []string{"Carol", "Dan"}}}
+	board := NewScoreboard()
+	// Carol's Bear duplicates Alice's across teams, so neither scores it
+	round := roundWith("B", map[string][]string{
+		"Alice": {"Bear", "Banana"},
+		"Bob":   {"", "Blueberry"},
+		"Carol": {"bear", "Bread"},
+		"Dan":   {"Bison", "Bagel"},
+	})
+	board.AddRound(scoreRound(&round, ScoringRules{Unique: 1}))
+	board.AddRound(map[string]int{"Bob": 2})
+	standings := board.TeamStandings(teams)
+	if len(standings) != 2 || standings[0].Team != "Red" || standings[0].Points != 4 || standings[1].Team != "Blue" || standings[1].Points != 3 {
+		t.Fatalf("standings %+v, want Red on 4 ahead of Blue on 3", standings)
+	}
+	if got := standings[0].Players; !reflect.DeepEqual(got, []Standing{{"Bob", 3}, {"Alice", 1}}) {
+		t.Errorf("Red's players %+v, want Bob on 3 ahead of Alice on 1", got)
+	}
+	if got := standings[1].Players; !reflect.DeepEqual(got, []Standing{{"Dan", 2}, {"Carol", 1}}) {
+		t.Errorf("Blue's players %+v, want Dan on 2 ahead of Carol on 1", got)
+	}
+
+	var out bytes.Buffer
+	board.printTeams(&out, teams)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 7 || !strings.Contains(lines[1], "Red\t4") || !strings.Contains(lines[2], "Bob\t3") || !strings.Contains(lines[4], "Blue\t3") {
+		t.Errorf("printTeams wrote\n%s", out.String())
+	}
+}
+
+func TestTeamTiebreaks(t *testing.T) {
+	teams := []Team{{"Red", []string{"Alice", "Bob"}}, {"Blue", []string{"Carol", "Dan"}}, {"Green", []string{"Eve"}}}
+	board := NewScoreboard()
+	board.Totals = map[string]int{"Alice": 2, "Bob": 2, "Carol": 3, "Dan": 1, "Eve": 6}
+	board.Duplicates = map[string]int{"Alice": 1, "Bob": 0, "Carol": 0, "Dan": 2}
+	for _, test := range []struct {
+		tiebreak  string
+		teams     []string
+		redPlayer string
+	}{
+		{"alphabetical", []string{"Green", "Blue", "Red"}, "Alice"},
+		// Blue's two duplicates to Red's one put Red ahead
+		{"duplicates", []string{"Green", "Red", "Blue"}, "Bob"},
+	} {
+		set(t, &TIEBREAK, test.tiebreak)
+		// Over and over, since map order changes from one run to the next
+		for range 20 {
+			standings := board.TeamStandings(teams)
+			got := []string{}
+			red := ""
+			for _, standing := range standings {
+				got = append(got, standing.Team)
+				if standing.Team == "Red" {
+					red = standing.Players[0].Player
+				}
+			}
+			if !slices.Equal(got, test.teams) || red != test.redPlayer {
+				t.Errorf("%s: teams %v led by %s on Red, want %v led by %s", test.tiebreak, got, red, test.teams, test.redPlayer)
+				break
+			}
+		}
+	}
+}
+
+func TestTiebreakPromptWithTeams(t *testing.T) {
+	out, code := runGame(t, "", "-teams", "Red:Alice;Blue:Bob", "-tiebreak", "prompt", "-plan", "1")
+	if code != 1 || !strings.Contains(out, "-tiebreak prompt plays off tied players, not teams") {
+		t.Errorf("exited %d, want 1 saying -tiebreak prompt doesn't work with -teams:\n%s", code, out)
+	}
+}
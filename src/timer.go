@@ -0,0 +1,462 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// preRoundCountdown counts down from n, one number per second of clock, then
+// says go. Cancelling ctx skips straight to the end.
+func preRoundCountdown(ctx context.Context, w io.Writer, clock Clock, n int) {
+	if n <= 0 {
+		return
+	}
+	for i := n; i > 0; i-- {
+		fmt.Fprintf(w, "%d...\n", i)
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(w, msg("go"))
+			return
+		case <-clock.After(time.Second):
+		}
+	}
+	fmt.Fprintln(w, msg("go"))
+}
+
+// rest waits out d on clock between rounds, counting down the seconds left
+// on a single line. PAUSE_KEY read from input pauses or resumes the game;
+// other input is ignored.
+func rest(w io.Writer, clock Clock, d time.Duration, input <-chan string) {
+	deadline := clock.Now().Add(d)
+	for left := d; left > 0; left = deadline.Sub(clock.Now()) {
+		status := strings.Repeat(" ", visibleWidth(msg("paused-label")))
+		if clockPaused(clock) {
+			status = msg("paused-label")
+		}
+		fmt.Fprintf(w, "\r"+msg("rest")+status, formatClock(time.Duration(remainingSeconds(left))*time.Second))
+		select {
+		case <-clock.After(untilNextSecond(left)):
+		case line, ok := <-input:
+			if !ok {
+				input = nil
+			} else if strings.TrimSpace(line) == PAUSE_KEY {
+				togglePause(clock)
+			}
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// roundTimer tracks how much of a round has elapsed, excluding time spent
+// paused. It's safe for concurrent use.
+type roundTimer struct {
+	mu      sync.Mutex
+	total   time.Duration
+	elapsed time.Duration // accumulated before the current run
+	started time.Time     // start of the current run; zero while paused
+}
+
+func newRoundTimer(total time.Duration, now time.Time) *roundTimer {
+	return &roundTimer{total: total, started: now}
+}
+
+func (t *roundTimer) Paused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.started.IsZero()
+}
+
+func (t *roundTimer) Total() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+func (t *roundTimer) Elapsed(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.elapsedAt(now)
+}
+
+func (t *roundTimer) Remaining(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total - t.elapsedAt(now)
+}
+
+func (t *roundTimer) Done(now time.Time) bool {
+	return t.Remaining(now) <= 0
+}
+
+func (t *roundTimer) Pause(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.started.IsZero() {
+		t.elapsed = t.elapsedAt(now)
+		t.started = time.Time{}
+	}
+}
+
+func (t *roundTimer) Resume(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started.IsZero() {
+		t.started = now
+	}
+}
+
+func (t *roundTimer) Toggle(now time.Time) {
+	if t.Paused() {
+		t.Resume(now)
+	} else {
+		t.Pause(now)
+	}
+}
+
+// Extend adds d to the round's length.
+func (t *roundTimer) Extend(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total += d
+}
+
+func (t *roundTimer) elapsedAt(now time.Time) time.Duration {
+	if t.started.IsZero() {
+		return t.elapsed
+	}
+	return t.elapsed + now.Sub(t.started)
+}
+
+// How a round's timer stopped.
+type timerResult int
+
+const (
+	TIME_UP timerResult = iota
+	ENDED_EARLY
+	REDRAWN // abandoned so the round can be drawn again
+	UNDONE  // taken back, so the next round is drawn the same
+	QUIT
+)
+
+func (r timerResult) String() string {
+	switch r {
+	case TIME_UP:
+		return "time up"
+	case ENDED_EARLY:
+		return "ended early"
+	case REDRAWN:
+		return "redrawn"
+	case UNDONE:
+		return "undone"
+	case QUIT:
+		return "quit"
+	}
+	return fmt.Sprintf("timerResult(%d)", int(r))
+}
+
+// countdown draws timer on view until it runs out on clock or ctx is
+// cancelled, then returns how it stopped and the time that ran. Rather than
+// polling, it wakes as each whole second of the remaining time passes, and
+// every resolution in between if that's shorter, timed from the clock each
+// time so waits never add up to drift, and not at all while paused.
+// Lines read from input control the clock: EXTEND_KEY adds EXTEND_BY,
+// SKIP_KEY ends the round, REDRAW_KEY abandons it, UNDO_KEY takes it back
+// if entered within UNDO_WINDOW, PAUSE_KEY pauses or resumes the whole game,
+// QUIT_KEY quits, and an empty line pauses or resumes the timer alone.
+// Anything else is noted on view as no key and otherwise ignored. If reveal
+// is set, its prompts are shown on view as their times come.
+func countdown(ctx context.Context, w io.Writer, view timerView, clock Clock, timer *roundTimer, resolution time.Duration, input <-chan string, reveal *stagger) (timerResult, time.Duration) {
+	view.Start(w)
+	defer view.Stop(w)
+	last := timer.Total() // remaining time as of the previous tick
+	revealed := 0         // prompts shown so far
+	for now := clock.Now(); !timer.Done(now); now = clock.Now() {
+		remaining := timer.Remaining(now)
+		for reveal != nil && revealed < len(reveal.at) && timer.Elapsed(now) >= reveal.at[revealed] {
+			view.Reveal(w, revealed+1, reveal.prompts[revealed])
+			revealed++
+		}
+		for _, t := range crossed(WARN_AT, last, remaining) {
+			view.Note(w, fmt.Sprintf(msg("time-left"), formatClock(t)))
+		}
+		if WARN_BEEPS && len(crossed(BEEP_AT, last, remaining)) > 0 {
+			ring(w, 1)
+		}
+		last = remaining
+
+		bar := ""
+		if BAR_WIDTH > 0 {
+			bar = " " + progressBar(timer.Elapsed(now), timer.Total(), BAR_WIDTH)
+		}
+		label := timerLabel(timer.Elapsed(now), remaining, timerShowing())
+		paused := timer.Paused() || clockPaused(clock)
+		view.Tick(w, paint(timerColor(remaining, timer.Total()), label), bar, paused)
+
+		var wake <-chan time.Time // nil, so never fires, while paused
+		if !paused {
+			wait := min(untilNextSecond(remaining), resolution)
+			if reveal != nil && revealed < len(reveal.at) {
+				wait = min(wait, reveal.at[revealed]-timer.Elapsed(now))
+			}
+			wake = clock.After(wait)
+		}
+		select {
+		case <-ctx.Done():
+			return ENDED_EARLY, timer.Elapsed(clock.Now())
+		case line, ok := <-input:
+			if !ok {
+				input = nil
+				continue
+			}
+			switch strings.TrimSpace(line) {
+			case EXTEND_KEY:
+				timer.Extend(EXTEND_BY)
+			case SKIP_KEY:
+				return ENDED_EARLY, timer.Elapsed(clock.Now())
+			case REDRAW_KEY:
+				return REDRAWN, timer.Elapsed(clock.Now())
+			case UNDO_KEY:
+				if elapsed := timer.Elapsed(clock.Now()); elapsed < UNDO_WINDOW {
+					return UNDONE, elapsed
+				}
+				view.Note(w, fmt.Sprintf(msg("undo-late"), UNDO_WINDOW))
+			case PAUSE_KEY:
+				togglePause(clock)
+			case QUIT_KEY:
+				return QUIT, timer.Elapsed(clock.Now())
+			case "":
+				timer.Toggle(clock.Now())
+			default:
+				view.Note(w, fmt.Sprintf(msg("unknown-key"), strings.TrimSpace(line)))
+			}
+		case <-wake:
+		}
+	}
+	return TIME_UP, timer.Elapsed(clock.Now())
+}
+
+// stagger schedules a round's prompts to be revealed one at a time.
+type stagger struct {
+	prompts []string
+	at      []time.Duration // time into the round each prompt is revealed
+}
+
+// newStagger spreads prompts evenly over the first part of a round, showing
+// the first straight away and the last once over has elapsed.
+func newStagger(prompts []string, over time.Duration) *stagger {
+	s := &stagger{prompts: prompts, at: []time.Duration{}}
+	for i := range prompts {
+		at := time.Duration(0)
+		if len(prompts) > 1 {
+			at = over * time.Duration(i) / time.Duration(len(prompts)-1)
+		}
+		s.at = append(s.at, at)
+	}
+	return s
+}
+
+// remainingSeconds rounds the time left up to whole seconds, so the timer
+// shows a round's full length until a second has passed and 0 only once time
+// is up. Woken on each second, it counts down without skipping or repeating.
+func remainingSeconds(remaining time.Duration) int {
+	if remaining <= 0 {
+		return 0
+	}
+	return int((remaining + time.Second - 1) / time.Second)
+}
+
+// MIN_RESOLUTION is the shortest time allowed between timer redraws.
+const MIN_RESOLUTION = 10 * time.Millisecond
+
+// clampResolution brings resolution between MIN_RESOLUTION and the round's
+// duration, and rounds anything over a second to whole seconds, since the
+// clock only shows whole seconds. It returns why it changed resolution, or ""
+// if it didn't.
+func clampResolution(resolution, duration time.Duration) (time.Duration, string) {
+	switch {
+	case resolution < MIN_RESOLUTION:
+		return MIN_RESOLUTION, fmt.Sprintf("%s is too fine; using %s", resolution, MIN_RESOLUTION)
+	case resolution > duration:
+		return duration, fmt.Sprintf("%s is longer than the round; using %s", resolution, duration)
+	case resolution > time.Second && resolution%time.Second != 0:
+		rounded := resolution.Round(time.Second)
+		return rounded, fmt.Sprintf("%s isn't whole seconds; using %s", resolution, rounded)
+	}
+	return resolution, ""
+}
+
+// untilNextSecond returns how long until remainingSeconds next changes.
+func untilNextSecond(remaining time.Duration) time.Duration {
+	if r := remaining % time.Second; r > 0 {
+		return r
+	}
+	return time.Second
+}
+
+// TIME_DISPLAYS are the ways -time-display can render times: "mm ss" as in
+// 2m5s, "m:ss" as in 2:05, and "seconds" as in 125 seconds.
+var TIME_DISPLAYS = []string{"mm ss", "m:ss", "seconds"}
+
+// formatClock renders d in whole seconds in the TIME_DISPLAY style. Every
+// time the players see, on the timer, in warnings and in summaries, goes
+// through it.
+func formatClock(d time.Duration) string {
+	secs := max(int(d.Seconds()), 0)
+	switch TIME_DISPLAY {
+	case "m:ss":
+		return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+	case "seconds":
+		if secs == 1 {
+			return msg("one-second")
+		}
+		return fmt.Sprintf(msg("seconds"), secs)
+	}
+	return fmt.Sprintf("%dm%ds", secs/60, secs%60)
+}
+
+// What the timer line shows.
+type timerShows int
+
+const (
+	SHOWS_REMAINING timerShows = iota
+	SHOWS_ELAPSED
+	SHOWS_BOTH
+)
+
+// timerShowing is what -elapsed and -both set the timer line to show.
+func timerShowing() timerShows {
+	switch {
+	case SHOW_BOTH:
+		return SHOWS_BOTH
+	case SHOW_ELAPSED:
+		return SHOWS_ELAPSED
+	}
+	return SHOWS_REMAINING
+}
+
+// timerLabel renders the timer line's text, counting up, down or both. The
+// elapsed time is rounded down to whole seconds and the remaining time up, so
+// shown together they add up to the round's length.
+func timerLabel(elapsed, remaining time.Duration, show timerShows) string {
+	left := formatClock(time.Duration(remainingSeconds(remaining)) * time.Second)
+	switch show {
+	case SHOWS_ELAPSED:
+		return fmt.Sprintf(ELAPSED_FORMAT, formatClock(elapsed))
+	case SHOWS_BOTH:
+		return fmt.Sprintf(BOTH_FORMAT, formatClock(elapsed), left)
+	}
+	return fmt.Sprintf(TIMER_FORMAT, left)
+}
+
+// crossed returns the thresholds passed as the remaining time dropped from
+// last to remaining. While the remaining time only goes down, successive calls
+// never return the same threshold twice, and thresholds at or above the
+// round's full length never fire.
+func crossed(thresholds []time.Duration, last, remaining time.Duration) []time.Duration {
+	hit := []time.Duration{}
+	for _, t := range thresholds {
+		if remaining <= t && t < last {
+			hit = append(hit, t)
+		}
+	}
+	return hit
+}
+
+// ring sounds the terminal bell n times, BELL_GAP apart, unless SILENT is set.
+func ring(w io.Writer, n int) {
+	if SILENT {
+		return
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			time.Sleep(BELL_GAP)
+		}
+		fmt.Fprint(w, "\a")
+	}
+}
+
+// progressBar renders how much of total has elapsed as a bar width
+// characters wide, including its brackets, followed by a percentage.
+func progressBar(elapsed, total time.Duration, width int) string {
+	frac := 1.0
+	if total > 0 {
+		frac = float64(elapsed) / float64(total)
+	}
+	frac = max(0, min(frac, 1))
+	inner := max(width-2, 0)
+	filled := int(frac * float64(inner))
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("#", filled), strings.Repeat("-", inner-filled), int(frac*100))
+}
+
+// interrupter turns interrupt signals into round cancellation. An interrupt
+// during a round ends that round; a second one within QUIT_WINDOW, or one
+// outside a round, quits the program.
+type interrupter struct {
+	out    io.Writer
+	onQuit func() int // called before exiting, if set, returning the exit code
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	last   time.Time
+}
+
+// listen handles signals from sigs until the channel is closed. Any signal
+// but an interrupt, such as one of STOP_SIGNALS, quits straight away.
+func (in *interrupter) listen(sigs <-chan os.Signal) {
+	for sig := range sigs {
+		in.mu.Lock()
+		now := time.Now()
+		quit := sig != os.Interrupt || in.cancel == nil || now.Sub(in.last) < QUIT_WINDOW
+		if !quit {
+			in.cancel()
+			in.cancel = nil
+		}
+		in.last = now
+		in.mu.Unlock()
+
+		if quit {
+			fmt.Fprintln(in.out, "\n"+msg("bye"))
+			code := 0
+			if in.onQuit != nil {
+				code = in.onQuit()
+			}
+			os.Exit(code)
+		}
+	}
+}
+
+// startRound returns a context that's cancelled by the next interrupt.
+func (in *interrupter) startRound() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	in.mu.Lock()
+	in.cancel = cancel
+	in.mu.Unlock()
+	return ctx
+}
+
+// cancelRound ends the active round early, as a single interrupt would, and
+// reports whether there was one.
+func (in *interrupter) cancelRound() bool {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.cancel == nil {
+		return false
+	}
+	in.cancel()
+	in.cancel = nil
+	return true
+}
+
+// endRound releases the active round's context, if any.
+func (in *interrupter) endRound() {
+	in.mu.Lock()
+	if in.cancel != nil {
+		in.cancel()
+		in.cancel = nil
+	}
+	in.mu.Unlock()
+}
@@ -0,0 +1,778 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// set sets *v to value until the test ends.
+func set[T any](t *testing.T, v *T, value T) {
+	old := *v
+	*v = value
+	t.Cleanup(func() { *v = old })
+}
+
+type countdownResult struct {
+	result  timerResult
+	elapsed time.Duration
+}
+
+// startCountdown runs countdown on clock in the background, quietly, reading
+// input, and sends how it ended on the returned channel.
+func startCountdown(ctx context.Context, clock Clock, timer *roundTimer, input <-chan string) <-chan countdownResult {
+	done := make(chan countdownResult, 1)
+	go func() {
+		result, elapsed := countdown(ctx, io.Discard, quietView{}, clock, timer, time.Second, input, nil)
+		done <- countdownResult{result, elapsed}
+	}()
+	return done
+}
+
+// fakeClock is a Clock that only moves when Advance moves it. With auto set,
+// After moves it on by the time asked for itself and fires straight away, so
+// whatever waits on it runs through without waiting.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	auto    bool
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	at := c.now.Add(d)
+	if c.auto && at.After(c.now) {
+		c.now = at
+	}
+	if at.After(c.now) {
+		c.waiters = append(c.waiters, fakeWaiter{at, ch})
+	} else {
+		ch <- c.now
+	}
+	return ch
+}
+
+// Advance moves the clock on by d, firing everything due by then.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	waiting := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			waiting = append(waiting, waiter)
+		} else {
+			waiter.ch <- c.now
+		}
+	}
+	c.waiters = waiting
+}
+
+// BlockUntil waits until n calls to After are waiting to fire.
+func (c *fakeClock) BlockUntil(t *testing.T, n int) {
+	t.Helper()
+	eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.waiters) >= n
+	})
+}
+
+// eventually waits for done to report true, failing the test if it takes
+// more than a few seconds.
+func eventually(t *testing.T, done func() bool) {
+	t.Helper()
+	for start := time.Now(); !done(); time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("gave up waiting")
+		}
+	}
+}
+
+func TestRoundTimerPauseAndResume(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	timer := newRoundTimer(60*time.Second, start)
+	steps := []struct {
+		name      string
+		do        func()
+		now       int
+		paused    bool
+		remaining time.Duration
+	}{
+		{"running", func() {}, 10, false, 50 * time.Second},
+		{"paused", func() { timer.Pause(at(10)) }, 40, true, 50 * time.Second},
+		{"paused twice", func() { timer.Pause(at(40)) }, 45, true, 50 * time.Second},
+		{"resumed", func() { timer.Resume(at(45)) }, 55, false, 40 * time.Second},
+		{"resumed twice", func() { timer.Resume(at(55)) }, 60, false, 35 * time.Second},
+		{"toggled off", func() { timer.Toggle(at(60)) }, 100, true, 35 * time.Second},
+		{"toggled on", func() { timer.Toggle(at(100)) }, 135, false, 0},
+	}
+	for _, step := range steps {
+		step.do()
+		if timer.Paused() != step.paused {
+			t.Errorf("%s: paused = %v, want %v", step.name, timer.Paused(), step.paused)
+		}
+		if remaining := timer.Remaining(at(step.now)); remaining != step.remaining {
+			t.Errorf("%s: remaining = %s, want %s", step.name, remaining, step.remaining)
+		}
+	}
+	if !timer.Done(at(135)) {
+		t.Error("timer isn't done once the time has run")
+	}
+}
+
+func TestCountdownPauseKeepsRemainingTime(t *testing.T) {
+	clock := newFakeClock()
+	timer := newRoundTimer(10*time.Second, clock.Now())
+	input := make(chan string)
+	done := startCountdown(context.Background(), clock, timer, input)
+
+	clock.BlockUntil(t, 1)
+	clock.Advance(3 * time.Second)
+	clock.BlockUntil(t, 1)
+	input <- ""
+	eventually(t, timer.Paused)
+	clock.Advance(time.Minute)
+	input <- ""
+	eventually(t, func() bool { return !timer.Paused() })
+	for i := 0; i < 7; i++ {
+		clock.BlockUntil(t, 1)
+		clock.Advance(time.Second)
+	}
+	if got := <-done; got.result != TIME_UP || got.elapsed != 10*time.Second {
+		t.Errorf("countdown = %s after %s, want time up after 10s", got.result, got.elapsed)
+	}
+}
+
+func TestCountdownIgnoresUnknownKeys(t *testing.T) {
+	clock := newFakeClock()
+	timer := newRoundTimer(10*time.Second, clock.Now())
+	input := make(chan string)
+	var out lockedBuffer
+	done := make(chan timerResult, 1)
+	go func() {
+		result, _ := countdown(context.Background(), &out, &lineView{}, clock, timer, time.Second, input, nil)
+		done <- result
+	}()
+	input <- " x "
+	input <- "" // pauses, where it'd resume had the unknown key paused
+	eventually(t, timer.Paused)
+	input <- SKIP_KEY
+	if result := <-done; result != ENDED_EARLY {
+		t.Fatalf("countdown = %s, want it ended early", result)
+	}
+	if note := fmt.Sprintf(msg("unknown-key"), "x"); !strings.Contains(out.String(), note) {
+		t.Errorf("countdown didn't say %q:\n%s", note, out.String())
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	for _, test := range []struct {
+		elapsed, total time.Duration
+		width          int
+		want           string
+	}{
+		{0, time.Minute, 12, "[----------]   0%"},
+		{15 * time.Second, time.Minute, 12, "[##--------]  25%"},
+		{30 * time.Second, time.Minute, 6, "[##--]  50%"},
+		{time.Minute, time.Minute, 12, "[##########] 100%"},
+		{2 * time.Minute, time.Minute, 12, "[##########] 100%"},
+		{-time.Second, time.Minute, 12, "[----------]   0%"},
+		{time.Second, 0, 4, "[##] 100%"},
+		{time.Second, time.Minute, 1, "[]   1%"},
+	} {
+		got := progressBar(test.elapsed, test.total, test.width)
+		if got != test.want {
+			t.Errorf("progressBar(%s, %s, %d) = %q, want %q", test.elapsed, test.total, test.width, got, test.want)
+		}
+		if test.width >= 2 && visibleWidth(got) != test.width+5 {
+			t.Errorf("progressBar(%s, %s, %d) is %d wide, want the bar %d wide", test.elapsed, test.total, test.width, visibleWidth(got), test.width)
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	set(t, &BELL_GAP, 0)
+	var out bytes.Buffer
+	ring(&out, 3)
+	if out.String() != "\a\a\a" {
+		t.Errorf("ring(3) wrote %q", out.String())
+	}
+	set(t, &SILENT, true)
+	out.Reset()
+	ring(&out, 3)
+	if out.Len() != 0 {
+		t.Errorf("ring(3) with SILENT wrote %q", out.String())
+	}
+}
+
+func TestCountdownWarningBeeps(t *testing.T) {
+	set(t, &WARN_BEEPS, true)
+	set(t, &BEEP_AT, []time.Duration{30 * time.Second, 10 * time.Second})
+	for _, test := range []struct {
+		total time.Duration
+		beeps int
+	}{
+		{time.Minute, 2},
+		{30 * time.Second, 1}, // no beep for the whole round being left
+		{5 * time.Second, 0},
+	} {
+		clock := newFakeClock()
+		clock.auto = true
+		var out bytes.Buffer
+		countdown(context.Background(), &out, quietView{}, clock, newRoundTimer(test.total, clock.Now()), time.Second, nil, nil)
+		if beeps := strings.Count(out.String(), "\a"); beeps != test.beeps {
+			t.Errorf("a %s round beeped %d times, want %d", test.total, beeps, test.beeps)
+		}
+	}
+}
+
+func TestCrossedFiresEachThresholdOnce(t *testing.T) {
+	thresholds := []time.Duration{60 * time.Second, 30 * time.Second, 10 * time.Second}
+	fired := map[time.Duration]int{}
+	last := 60 * time.Second // a one-minute round
+	for remaining := last; remaining >= 0; remaining -= 250 * time.Millisecond {
+		for _, threshold := range crossed(thresholds, last, remaining) {
+			fired[threshold]++
+		}
+		last = remaining
+	}
+	if fired[60*time.Second] != 0 || fired[30*time.Second] != 1 || fired[10*time.Second] != 1 {
+		t.Errorf("fired %v, want 30s and 10s once each and not the full length", fired)
+	}
+	if hit := crossed(thresholds, 45*time.Second, 5*time.Second); len(hit) != 2 {
+		t.Errorf("skipping past two thresholds fired %v, want both", hit)
+	}
+}
+
+func TestCountdownWarnings(t *testing.T) {
+	set(t, &WARN_AT, []time.Duration{30 * time.Second, 10 * time.Second})
+	clock := newFakeClock()
+	clock.auto = true
+	var out bytes.Buffer
+	result, elapsed := countdown(context.Background(), &out, quietView{}, clock, newRoundTimer(time.Minute, clock.Now()), 100*time.Millisecond, nil, nil)
+	if result != TIME_UP || elapsed != time.Minute {
+		t.Errorf("countdown = %s after %s, want time up after 1m", result, elapsed)
+	}
+	if out.String() != "0m30s left!\n0m10s left!\n" {
+		t.Errorf("countdown warned %q", out.String())
+	}
+}
+
+func TestRoundTimerExtend(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timer := newRoundTimer(time.Minute, start)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timer.Extend(30 * time.Second)
+			timer.Remaining(start)
+		}()
+	}
+	wg.Wait()
+	if total := timer.Total(); total != 6*time.Minute {
+		t.Errorf("total = %s after ten 30s extensions, want 6m", total)
+	}
+	if remaining := timer.Remaining(start.Add(5 * time.Minute)); remaining != time.Minute {
+		t.Errorf("remaining = %s 5m in, want 1m", remaining)
+	}
+}
+
+func TestCountdownExtendKey(t *testing.T) {
+	set(t, &EXTEND_BY, 30*time.Second)
+	clock := newFakeClock()
+	timer := newRoundTimer(10*time.Second, clock.Now())
+	input := make(chan string)
+	done := startCountdown(context.Background(), clock, timer, input)
+	clock.BlockUntil(t, 1)
+	input <- EXTEND_KEY
+	eventually(t, func() bool { return timer.Total() == 40*time.Second })
+	clock.Advance(10 * time.Second)
+	select {
+	case got := <-done:
+		t.Fatalf("countdown ended (%s) at the original length", got.result)
+	case <-time.After(10 * time.Millisecond):
+	}
+	for i := 0; i < 30; i++ {
+		clock.BlockUntil(t, 1)
+		clock.Advance(time.Second)
+	}
+	if got := <-done; got.result != TIME_UP || got.elapsed != 40*time.Second {
+		t.Errorf("countdown = %s after %s, want time up after 40s", got.result, got.elapsed)
+	}
+}
+
+func TestTimerLabel(t *testing.T) {
+	for _, test := range []struct {
+		elapsed, remaining time.Duration
+		show               timerShows
+		want               string
+	}{
+		{0, 2 * time.Minute, SHOWS_REMAINING, "Remaining time: 2m0s"},
+		{500 * time.Millisecond, 119500 * time.Millisecond, SHOWS_REMAINING, "Remaining time: 2m0s"},
+		{65 * time.Second, 55 * time.Second, SHOWS_REMAINING, "Remaining time: 0m55s"},
+		{0, 2 * time.Minute, SHOWS_ELAPSED, "Elapsed time: 0m0s"},
+		{65500 * time.Millisecond, 54500 * time.Millisecond, SHOWS_ELAPSED, "Elapsed time: 1m5s"},
+		{65500 * time.Millisecond, 54500 * time.Millisecond, SHOWS_BOTH, "Elapsed 1m5s / Remaining 0m55s"},
+		{2 * time.Minute, 0, SHOWS_BOTH, "Elapsed 2m0s / Remaining 0m0s"},
+	} {
+		if got := timerLabel(test.elapsed, test.remaining, test.show); got != test.want {
+			t.Errorf("timerLabel(%s, %s, %d) = %q, want %q", test.elapsed, test.remaining, test.show, got, test.want)
+		}
+	}
+}
+
+func TestBothTimesAddUp(t *testing.T) {
+	set(t, &TIME_DISPLAY, "m:ss")
+	for _, total := range []time.Duration{2*time.Minute + 15*time.Second, 45 * time.Second, 61 * time.Second} {
+		for elapsed := time.Duration(0); elapsed <= total; elapsed += 250 * time.Millisecond {
+			label := timerLabel(elapsed, total-elapsed, SHOWS_BOTH)
+			var em, es, rm, rs int
+			if _, err := fmt.Sscanf(label, "Elapsed %d:%d / Remaining %d:%d", &em, &es, &rm, &rs); err != nil {
+				t.Fatalf("%s into %s: can't read %q: %v", elapsed, total, label, err)
+			}
+			if got := time.Duration(em*60+es+rm*60+rs) * time.Second; got != total {
+				t.Errorf("%s into %s: %q adds up to %s", elapsed, total, label, got)
+			}
+		}
+	}
+}
+
+func TestBothTimesFormats(t *testing.T) {
+	for _, test := range []struct {
+		display string
+		elapsed time.Duration
+		want    string
+	}{
+		{"mm ss", 45 * time.Second, "Elapsed 0m45s / Remaining 2m15s"},
+		{"mm ss", 90500 * time.Millisecond, "Elapsed 1m30s / Remaining 1m30s"},
+		{"m:ss", 45 * time.Second, "Elapsed 0:45 / Remaining 2:15"},
+		{"m:ss", 179 * time.Second, "Elapsed 2:59 / Remaining 0:01"},
+		{"seconds", 59 * time.Second, "Elapsed 59 seconds / Remaining 121 seconds"},
+		{"seconds", 179200 * time.Millisecond, "Elapsed 179 seconds / Remaining 1 second"},
+	} {
+		set(t, &TIME_DISPLAY, test.display)
+		if got := timerLabel(test.elapsed, 3*time.Minute-test.elapsed, SHOWS_BOTH); got != test.want {
+			t.Errorf("%q at %s: got %q, want %q", test.display, test.elapsed, got, test.want)
+		}
+	}
+}
+
+func TestFormatClock(t *testing.T) {
+	for _, test := range []struct {
+		d                  time.Duration
+		mmss, mss, seconds string
+	}{
+		{0, "0m0s", "0:00", "0 seconds"},
+		{time.Second, "0m1s", "0:01", "1 second"},
+		{1900 * time.Millisecond, "0m1s", "0:01", "1 second"},
+		{9 * time.Second, "0m9s", "0:09", "9 seconds"},
+		{10 * time.Second, "0m10s", "0:10", "10 seconds"},
+		{59 * time.Second, "0m59s", "0:59", "59 seconds"},
+		{time.Minute, "1m0s", "1:00", "60 seconds"},
+		{65 * time.Second, "1m5s", "1:05", "65 seconds"},
+		{2 * time.Minute, "2m0s", "2:00", "120 seconds"},
+		{10*time.Minute + 9*time.Second, "10m9s", "10:09", "609 seconds"},
+		{75 * time.Minute, "75m0s", "75:00", "4500 seconds"},
+		{-5 * time.Second, "0m0s", "0:00", "0 seconds"},
+	} {
+		for display, want := range map[string]string{"mm ss": test.mmss, "m:ss": test.mss, "seconds": test.seconds} {
+			set(t, &TIME_DISPLAY, display)
+			if got := formatClock(test.d); got != want {
+				t.Errorf("%q: formatClock(%s) = %q, want %q", display, test.d, got, want)
+			}
+		}
+	}
+	if len(TIME_DISPLAYS) != 3 {
+		t.Errorf("TIME_DISPLAYS = %q, want a test for each", TIME_DISPLAYS)
+	}
+
+	set(t, &LANGUAGE, "es")
+	set(t, &TIME_DISPLAY, "seconds")
+	for d, want := range map[time.Duration]string{time.Second: "1 segundo", 65 * time.Second: "65 segundos"} {
+		if got := formatClock(d); got != want {
+			t.Errorf("in Spanish, formatClock(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestTimerShowing(t *testing.T) {
+	for _, test := range []struct {
+		elapsed, both bool
+		want          timerShows
+	}{
+		{false, false, SHOWS_REMAINING},
+		{true, false, SHOWS_ELAPSED},
+		{false, true, SHOWS_BOTH},
+		{true, true, SHOWS_BOTH},
+	} {
+		set(t, &SHOW_ELAPSED, test.elapsed)
+		set(t, &SHOW_BOTH, test.both)
+		if got := timerShowing(); got != test.want {
+			t.Errorf("-elapsed=%v -both=%v shows %d, want %d", test.elapsed, test.both, got, test.want)
+		}
+	}
+}
+
+// tickView is a quiet timerView that records every label it's asked to
+// show, marking the paused ones.
+type tickView struct {
+	quietView
+	mu     sync.Mutex
+	labels []string
+}
+
+func (v *tickView) Tick(w io.Writer, label, bar string, paused bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if paused {
+		label += " (paused)"
+	}
+	v.labels = append(v.labels, label)
+}
+
+// shown returns the labels so far.
+func (v *tickView) shown() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return slices.Clone(v.labels)
+}
+
+func TestRemainingSeconds(t *testing.T) {
+	for _, test := range []struct {
+		remaining time.Duration
+		want      int
+	}{
+		{3 * time.Minute, 180},
+		{179*time.Second + time.Nanosecond, 180},
+		{179 * time.Second, 179},
+		{900 * time.Millisecond, 1},
+		{time.Nanosecond, 1},
+		{0, 0},
+		{-time.Second, 0},
+	} {
+		if got := remainingSeconds(test.remaining); got != test.want {
+			t.Errorf("remainingSeconds(%s) = %d, want %d", test.remaining, got, test.want)
+		}
+	}
+}
+
+func TestUntilNextSecond(t *testing.T) {
+	for _, test := range []struct {
+		remaining, want time.Duration
+	}{
+		{10 * time.Second, time.Second},
+		{9500 * time.Millisecond, 500 * time.Millisecond},
+		{time.Nanosecond, time.Nanosecond},
+	} {
+		if got := untilNextSecond(test.remaining); got != test.want {
+			t.Errorf("untilNextSecond(%s) = %s, want %s", test.remaining, got, test.want)
+		}
+		// Waking then shows the next whole second down
+		if got, want := remainingSeconds(test.remaining-untilNextSecond(test.remaining)), remainingSeconds(test.remaining)-1; got != want {
+			t.Errorf("%s after waking at %s left shows %d, want %d", test.remaining-untilNextSecond(test.remaining), test.remaining, got, want)
+		}
+	}
+}
+
+func TestCountdownShowsEachSecondOnce(t *testing.T) {
+	set(t, &TIME_DISPLAY, "seconds")
+	set(t, &SHOW_ELAPSED, false)
+	set(t, &SHOW_BOTH, false)
+	set(t, &TIMER_FORMAT, "%s")
+	for _, test := range []struct {
+		total, resolution time.Duration
+		ticks             int
+	}{
+		{5 * time.Second, time.Second, 5},
+		{5 * time.Second, 250 * time.Millisecond, 20},
+		// Starting mid-second, the first wake lines up with the next one
+		{4500 * time.Millisecond, time.Second, 5},
+	} {
+		clock := newFakeClock()
+		clock.auto = true
+		view := &tickView{}
+		result, elapsed := countdown(context.Background(), io.Discard, view, clock, newRoundTimer(test.total, clock.Now()), test.resolution, nil, nil)
+		if result != TIME_UP || elapsed != test.total {
+			t.Errorf("%s at %s: %s after %s, want time up after %s", test.total, test.resolution, result, elapsed, test.total)
+		}
+		if len(view.labels) != test.ticks {
+			t.Errorf("%s at %s: drew the timer %d times, want %d", test.total, test.resolution, len(view.labels), test.ticks)
+		}
+		shown := []string{}
+		for _, label := range view.labels {
+			if len(shown) == 0 || shown[len(shown)-1] != label {
+				shown = append(shown, label)
+			}
+		}
+		if got := strings.Join(shown, ","); got != "5 seconds,4 seconds,3 seconds,2 seconds,1 second" {
+			t.Errorf("%s at %s: showed %s, want every second from 5 down to 1 once", test.total, test.resolution, got)
+		}
+	}
+}
+
+func TestCountdownFrames(t *testing.T) {
+	set(t, &TIME_DISPLAY, "m:ss")
+	set(t, &SHOW_ELAPSED, false)
+	set(t, &SHOW_BOTH, false)
+	set(t, &TIMER_FORMAT, "%s")
+	clock := newFakeClock()
+	timer := newRoundTimer(3*time.Second, clock.Now())
+	view := &tickView{}
+	input := make(chan string)
+	done := make(chan timerResult, 1)
+	go func() {
+		result, _ := countdown(context.Background(), io.Discard, view, clock, timer, time.Second, input, nil)
+		done <- result
+	}()
+	frames := func(want ...string) {
+		t.Helper()
+		eventually(t, func() bool { return len(view.shown()) >= len(want) })
+		if got := view.shown(); !slices.Equal(got, want) {
+			t.Fatalf("frames %q, want %q", got, want)
+		}
+	}
+
+	clock.BlockUntil(t, 1)
+	frames("0:03")
+	clock.Advance(time.Second)
+	clock.BlockUntil(t, 1)
+	frames("0:03", "0:02")
+	input <- "" // pause
+	frames("0:03", "0:02", "0:02 (paused)")
+	clock.Advance(time.Hour) // paused, nothing waits on the clock to redraw
+	input <- ""              // resume
+	frames("0:03", "0:02", "0:02 (paused)", "0:02")
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(t, 1)
+		clock.Advance(time.Second)
+	}
+	if result := <-done; result != TIME_UP {
+		t.Fatalf("countdown = %s, want time up", result)
+	}
+	frames("0:03", "0:02", "0:02 (paused)", "0:02", "0:01")
+}
+
+func TestPausableClock(t *testing.T) {
+	fake := newFakeClock()
+	clock := newPausableClock(fake)
+	start := clock.Now()
+	fired := clock.After(10 * time.Second)
+	fake.BlockUntil(t, 1)
+	fake.Advance(4 * time.Second)
+	if !clock.Toggle() || !clockPaused(clock) {
+		t.Fatal("Toggle didn't pause the clock")
+	}
+	fake.Advance(time.Hour)
+	if now := clock.Now(); now != start.Add(4*time.Second) {
+		t.Errorf("paused clock reads %s in, want 4s", now.Sub(start))
+	}
+	select {
+	case <-fired:
+		t.Fatal("After fired while paused")
+	default:
+	}
+	if togglePause(clock) {
+		t.Fatal("togglePause didn't resume the clock")
+	}
+	fake.BlockUntil(t, 1)
+	fake.Advance(6 * time.Second)
+	select {
+	case at := <-fired:
+		if at != start.Add(10*time.Second) {
+			t.Errorf("After fired %s in, want 10s", at.Sub(start))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("After didn't fire 10s in, not counting the pause")
+	}
+	if togglePause(realClock{}) || clockPaused(realClock{}) {
+		t.Error("paused the wall clock")
+	}
+}
+
+func TestPauseKeyFreezesTheRound(t *testing.T) {
+	fake := newFakeClock()
+	clock := newPausableClock(fake)
+	start := clock.Now()
+	timer := newRoundTimer(10*time.Second, start)
+	input := make(chan string)
+	done := startCountdown(context.Background(), clock, timer, input)
+	for range 3 {
+		fake.BlockUntil(t, 1)
+		fake.Advance(time.Second)
+	}
+
+	// An hour away from the game costs the round nothing
+	fake.BlockUntil(t, 1)
+	input <- PAUSE_KEY
+	eventually(t, func() bool { return clockPaused(clock) })
+	fake.Advance(time.Hour)
+	select {
+	case got := <-done:
+		t.Fatalf("round ended (%s) while paused", got.result)
+	case <-time.After(10 * time.Millisecond):
+	}
+	if timer.Paused() {
+		t.Error("pausing the game paused the round's own timer too")
+	}
+	if left := timer.Remaining(clock.Now()); left != 7*time.Second {
+		t.Errorf("%s left after the pause, want 7s", left)
+	}
+	input <- PAUSE_KEY
+	var got countdownResult
+	for finished := false; !finished; {
+		eventually(t, func() bool {
+			select {
+			case got = <-done:
+				finished = true
+				return true
+			default:
+			}
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			return len(fake.waiters) > 0
+		})
+		if !finished {
+			fake.Advance(time.Second)
+		}
+	}
+	if got.result != TIME_UP || got.elapsed != 10*time.Second {
+		t.Errorf("countdown = %s after %s, want time up after 10s", got.result, got.elapsed)
+	}
+	if ran := clock.Now().Sub(start); ran != 10*time.Second {
+		t.Errorf("the game ran %s, want 10s besides the pause", ran)
+	}
+	if passed := fake.Now().Sub(start); passed < time.Hour+10*time.Second {
+		t.Errorf("round ended %s in, before its 10s had run on top of the hour's pause", passed)
+	}
+}
+
+func TestClampResolution(t *testing.T) {
+	for _, test := range []struct {
+		resolution, duration, want time.Duration
+		warns                      bool
+	}{
+		{time.Second, 3 * time.Minute, time.Second, false},
+		{100 * time.Millisecond, 3 * time.Minute, 100 * time.Millisecond, false},
+		{MIN_RESOLUTION, 3 * time.Minute, MIN_RESOLUTION, false},
+		{5 * time.Second, 3 * time.Minute, 5 * time.Second, false},
+		{time.Millisecond, 3 * time.Minute, MIN_RESOLUTION, true},
+		{0, 3 * time.Minute, MIN_RESOLUTION, true},
+		{-time.Second, 3 * time.Minute, MIN_RESOLUTION, true},
+		{time.Hour, 3 * time.Minute, 3 * time.Minute, true},
+		{1500 * time.Millisecond, 3 * time.Minute, 2 * time.Second, true},
+		{2400 * time.Millisecond, 3 * time.Minute, 2 * time.Second, true},
+		{3 * time.Minute, 3 * time.Minute, 3 * time.Minute, false},
+	} {
+		got, why := clampResolution(test.resolution, test.duration)
+		if got != test.want || (why != "") != test.warns {
+			t.Errorf("clampResolution(%s, %s) = %s, %q; want %s, warning %v", test.resolution, test.duration, got, why, test.want, test.warns)
+		}
+	}
+}
+
+func TestCoarseResolutionStillShowsEachSecond(t *testing.T) {
+	set(t, &TIME_DISPLAY, "seconds")
+	set(t, &SHOW_ELAPSED, false)
+	set(t, &SHOW_BOTH, false)
+	set(t, &TIMER_FORMAT, "%s")
+	clock := newFakeClock()
+	clock.auto = true
+	view := &tickView{}
+	countdown(context.Background(), io.Discard, view, clock, newRoundTimer(3*time.Second, clock.Now()), 5*time.Second, nil, nil)
+	if got := strings.Join(view.shown(), ","); got != "3 seconds,2 seconds,1 second" {
+		t.Errorf("a 5s resolution showed %s, want each second", got)
+	}
+}
+
+func TestNewStagger(t *testing.T) {
+	for _, test := range []struct {
+		prompts int
+		over    time.Duration
+		want    []time.Duration
+	}{
+		{1, time.Minute, []time.Duration{0}},
+		{3, time.Minute, []time.Duration{0, 30 * time.Second, time.Minute}},
+		{4, 3 * time.Second, []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second}},
+		{0, time.Minute, []time.Duration{}},
+	} {
+		s := newStagger(TEST_PROMPTS[:test.prompts], test.over)
+		if !slices.Equal(s.at, test.want) {
+			t.Errorf("%d prompts over %s revealed at %v, want %v", test.prompts, test.over, s.at, test.want)
+		}
+	}
+}
+
+// revealView is a quiet timerView that records when, by clock, each prompt
+// is revealed.
+type revealView struct {
+	quietView
+	clock Clock
+	start time.Time
+	at    []time.Duration
+	shown []string
+}
+
+func (v *revealView) Reveal(w io.Writer, n int, prompt string) {
+	v.at = append(v.at, v.clock.Now().Sub(v.start))
+	v.shown = append(v.shown, fmt.Sprintf("%d. %s", n, prompt))
+}
+
+func TestCountdownRevealsOnSchedule(t *testing.T) {
+	clock := newFakeClock()
+	clock.auto = true
+	view := &revealView{clock: clock, start: clock.Now()}
+	reveal := newStagger([]string{"Animals", "Bands", "Cars"}, 5*time.Second)
+	result, _ := countdown(context.Background(), io.Discard, view, clock, newRoundTimer(10*time.Second, clock.Now()), time.Second, nil, reveal)
+	if result != TIME_UP {
+		t.Fatalf("countdown = %s, want time up", result)
+	}
+	if want := []time.Duration{0, 2500 * time.Millisecond, 5 * time.Second}; !slices.Equal(view.at, want) {
+		t.Errorf("revealed at %v, want %v", view.at, want)
+	}
+	if want := []string{"1. Animals", "2. Bands", "3. Cars"}; !slices.Equal(view.shown, want) {
+		t.Errorf("revealed %q, want %q", view.shown, want)
+	}
+}
+
+func TestStaggeredRoundHoldsPromptsBack(t *testing.T) {
+	set(t, &QUIET, true)
+	set(t, &BELLS, 0)
+	round, out := playTestRound(t, Config{Duration: 10 * time.Second, NumPrompts: 3, Letters: []rune("B"), Resolution: time.Second, Stagger: 4 * time.Second})
+	header, revealed, ok := strings.Cut(out, fmt.Sprintf(msg("stagger"), 4*time.Second))
+	if !ok {
+		t.Fatalf("round didn't announce the stagger:\n%s", out)
+	}
+	for i, prompt := range round.Prompts {
+		if strings.Contains(header, prompt) || !strings.Contains(revealed, fmt.Sprintf("%d.\t%s", i+1, prompt)) {
+			t.Errorf("prompt %q wasn't held back and revealed:\n%s", prompt, out)
+		}
+	}
+}
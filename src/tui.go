@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ANSI sequences for the full-screen display.
+const (
+	TUI_ENTER = "\033[?1049h\033[?25l" // switch to the alternate screen, hide cursor
+	TUI_LEAVE = "\033[?25h\033[?1049l" // show cursor, back to the main screen
+	TUI_CLEAR = "\033[H\033[2J"        // cursor home, clear screen
+)
+
+// timerView draws a running round's timer.
+type timerView interface {
+	Start(w io.Writer)
+	Tick(w io.Writer, label, bar string, paused bool)
+	Note(w io.Writer, msg string)
+	Reveal(w io.Writer, n int, prompt string) // shows prompt number n partway through the round
+	Stop(w io.Writer)
+}
+
+// lineView rewrites a single timer line in place with carriage returns. Each
+// update is padded with spaces to cover the last one rather than cleared with
+// an escape sequence, which older Windows consoles don't understand.
+type lineView struct {
+	width int // visible width of the line on screen, 0 if there's none
+}
+
+func (v *lineView) Start(w io.Writer) {}
+
+func (v *lineView) Tick(w io.Writer, label, bar string, paused bool) {
+	status := strings.Repeat(" ", visibleWidth(msg("paused-label"))+1)
+	if paused {
+		status = " " + msg("paused-label")
+	}
+	line := label + bar + status
+	n := visibleWidth(line)
+	fmt.Fprintf(w, "\r%s%s", line, strings.Repeat(" ", max(v.width-n, 0)))
+	v.width = n
+}
+
+func (v *lineView) Note(w io.Writer, msg string) {
+	fmt.Fprintf(w, "\n%s\n", msg)
+	v.width = 0
+}
+
+// Reveal writes the prompt over the timer line, which the next tick redraws
+// below it.
+func (v *lineView) Reveal(w io.Writer, n int, prompt string) {
+	line := fmt.Sprintf(PROMPT_FORMAT, painted{COLOR_DIM, n}, prompt)
+	fmt.Fprintf(w, "\r%s%s\n", line, strings.Repeat(" ", max(v.width-visibleWidth(line), 0)))
+	v.width = 0
+}
+
+// Stop ends the timer line, so whatever's printed next starts on a clean one.
+func (v *lineView) Stop(w io.Writer) {
+	if v.width > 0 {
+		fmt.Fprintln(w)
+	}
+	v.width = 0
+}
+
+// visibleWidth counts the characters s takes up on screen, skipping ANSI
+// escape sequences such as colors.
+func visibleWidth(s string) int {
+	n := 0
+	escape := false
+	for _, r := range s {
+		switch {
+		case escape:
+			escape = !unicode.IsLetter(r) // sequences end with a letter
+		case r == '\033':
+			escape = true
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// quietView draws nothing while the timer runs, showing only its notes.
+type quietView struct{}
+
+func (quietView) Start(w io.Writer) {}
+
+func (quietView) Tick(w io.Writer, label, bar string, paused bool) {}
+
+func (quietView) Note(w io.Writer, msg string) {
+	fmt.Fprintln(w, msg)
+}
+
+func (quietView) Reveal(w io.Writer, n int, prompt string) {
+	fmt.Fprintf(w, PROMPT_FORMAT+"\n", painted{COLOR_DIM, n}, prompt)
+}
+
+func (quietView) Stop(w io.Writer) {}
+
+// tuiView takes over the terminal for the round, redrawing the letter,
+// prompts, timer and key help on every tick so nothing scrolls.
+type tuiView struct {
+	round *Round
+	shown int // prompts revealed so far
+	notes []string
+}
+
+func (v *tuiView) Start(w io.Writer) {
+	fmt.Fprint(w, TUI_ENTER)
+}
+
+func (v *tuiView) Tick(w io.Writer, label, bar string, paused bool) {
+	var b strings.Builder
+	b.WriteString(TUI_CLEAR)
+	fmt.Fprintf(&b, msg("tui-title")+"\n\n", v.round.Number)
+	if len(v.round.Letters) > 0 {
+		fmt.Fprintf(&b, LETTER_FORMAT+"\n\n", painted{COLOR_LETTER, letterText(v.round.Letters)})
+	}
+	b.WriteString(msg("prompts") + "\n")
+	for i, prompt := range v.round.Prompts[:v.shown] {
+		fmt.Fprintf(&b, PROMPT_FORMAT+"\n", painted{COLOR_DIM, i + 1}, prompt)
+	}
+	fmt.Fprintf(&b, "\n%s%s\n", label, bar)
+	if paused {
+		b.WriteString(paint(COLOR_YELLOW, msg("paused-label")) + "\n")
+	} else {
+		b.WriteString("\n")
+	}
+	for _, note := range v.notes {
+		b.WriteString(note + "\n")
+	}
+	fmt.Fprintf(&b, "\n"+msg("tui-keys")+"\n", EXTEND_KEY, EXTEND_BY, SKIP_KEY, REDRAW_KEY, UNDO_KEY, PAUSE_KEY, QUIT_KEY)
+	io.WriteString(w, b.String())
+}
+
+func (v *tuiView) Note(w io.Writer, msg string) {
+	v.notes = append(v.notes, msg)
+}
+
+func (v *tuiView) Reveal(w io.Writer, n int, prompt string) {
+	v.shown = n
+}
+
+func (v *tuiView) Stop(w io.Writer) {
+	fmt.Fprint(w, TUI_LEAVE)
+}
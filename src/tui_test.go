@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineViewPadsShorterUpdates(t *testing.T) {
+	var out bytes.Buffer
+	view := &lineView{}
+	view.Tick(&out, "Remaining time: 1m0s", " [####      ]", false)
+	first := out.String()
+	out.Reset()
+	view.Tick(&out, "Remaining time: 59s", "", false)
+	second := out.String()
+	if !strings.HasPrefix(first, "\r") || !strings.HasPrefix(second, "\r") {
+		t.Errorf("updates %q and %q don't return to column zero", first, second)
+	}
+	if visibleWidth(second) != visibleWidth(first) {
+		t.Errorf("shorter update is %d wide, want it padded to the last one's %d", visibleWidth(second), visibleWidth(first))
+	}
+	out.Reset()
+	view.Tick(&out, "Remaining time: 58s", "", true)
+	// The paused label fills the room kept for it, so pausing doesn't change
+	// the line's width
+	var running bytes.Buffer
+	(&lineView{}).Tick(&running, "Remaining time: 58s", "", false)
+	if paused := out.String(); visibleWidth(paused) != visibleWidth(running.String()) || !strings.HasSuffix(paused, msg("paused-label")) {
+		t.Errorf("paused update %q, want it as wide as %q and marked paused", paused, running.String())
+	}
+}
+
+func TestLineViewPaddingIgnoresColor(t *testing.T) {
+	set(t, &COLOR, true)
+	var out bytes.Buffer
+	view := &lineView{}
+	view.Tick(&out, paint(COLOR_GREEN, "Remaining time: 10s"), "", false)
+	out.Reset()
+	view.Tick(&out, paint(COLOR_RED, "Remaining time: 9s"), "", false)
+	// One space covers the lost digit: color codes take up no room on screen
+	if plain := "\r" + "Remaining time: 9s" + strings.Repeat(" ", visibleWidth(msg("paused-label"))+1) + " "; visibleWidth(out.String()) != visibleWidth(plain) {
+		t.Errorf("update %q is %d wide, want %d", out.String(), visibleWidth(out.String()), visibleWidth(plain))
+	}
+}
+
+func TestLineViewEndsOnACleanLine(t *testing.T) {
+	var out bytes.Buffer
+	view := &lineView{}
+	view.Stop(&out)
+	if out.Len() != 0 {
+		t.Errorf("Stop with no timer line wrote %q", out.String())
+	}
+	view.Tick(&out, "Remaining time: 1s", "", false)
+	view.Stop(&out)
+	out.WriteString("Time's up!\n")
+	lines := strings.Split(out.String(), "\n")
+	if last := lines[len(lines)-2]; last != "Time's up!" {
+		t.Errorf("time's up shares its line: %q", last)
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	for _, test := range []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"1m0s", 4},
+		{COLOR_LETTER + "B" + COLOR_RESET, 1},
+		{"año", 3},
+	} {
+		if got := visibleWidth(test.s); got != test.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", test.s, got, test.want)
+		}
+	}
+}
+
+func TestQuietShowsNoTimer(t *testing.T) {
+	set(t, &QUIET, true)
+	set(t, &BAR_WIDTH, 10)
+	set(t, &COLOR, true)
+	set(t, &BELLS, 0)
+	set(t, &WARN_AT, []time.Duration{2 * time.Second})
+	_, out := playTestRound(t, Config{Duration: 5 * time.Second, NumPrompts: 2, Letters: []rune("B"), Resolution: 100 * time.Millisecond})
+	if strings.Contains(out, "Remaining time") || strings.Contains(out, "\r") || strings.Contains(out, "#") {
+		t.Errorf("quiet round drew the timer:\n%q", out)
+	}
+	for _, want := range []string{"B", msg("prompts"), fmt.Sprintf(msg("time-left"), "0m2s"), msg("times-up")} {
+		if !strings.Contains(out, want) {
+			t.Errorf("quiet round is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTimerLines(t *testing.T) {
+	set(t, &QUIET, false)
+	set(t, &BELLS, 0)
+	_, out := playTestRound(t, Config{Duration: 5 * time.Second, NumPrompts: 2, Letters: []rune("B"), Resolution: time.Second})
+	if n := strings.Count(out, "\rRemaining time"); n != 5 {
+		t.Errorf("drew the timer %d times, want 5:\n%q", n, out)
+	}
+}
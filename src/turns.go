@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// playerClocks keeps a time budget for each player, with at most one clock
+// running at a time.
+type playerClocks struct {
+	players []string
+	timers  map[string]*roundTimer
+	active  string // player whose clock is running, if any
+}
+
+// newPlayerClocks gives each player budget on a stopped clock.
+func newPlayerClocks(players []string, budget time.Duration, now time.Time) *playerClocks {
+	c := &playerClocks{players: players, timers: map[string]*roundTimer{}}
+	for _, player := range players {
+		timer := newRoundTimer(budget, now)
+		timer.Pause(now)
+		c.timers[player] = timer
+	}
+	return c
+}
+
+// Start runs player's clock, stopping whichever was running.
+func (c *playerClocks) Start(player string, now time.Time) {
+	c.Stop(now)
+	c.timers[player].Resume(now)
+	c.active = player
+}
+
+// Stop stops the running clock, if any.
+func (c *playerClocks) Stop(now time.Time) {
+	if c.active != "" {
+		c.timers[c.active].Pause(now)
+		c.active = ""
+	}
+}
+
+func (c *playerClocks) Remaining(player string, now time.Time) time.Duration {
+	return max(c.timers[player].Remaining(now), 0)
+}
+
+// Out reports whether player has used up their budget.
+func (c *playerClocks) Out(player string, now time.Time) bool {
+	return c.timers[player].Done(now)
+}
+
+// Left returns the players with time left, in turn order.
+func (c *playerClocks) Left(now time.Time) []string {
+	left := []string{}
+	for _, player := range c.players {
+		if !c.Out(player, now) {
+			left = append(left, player)
+		}
+	}
+	return left
+}
+
+// String renders every player's remaining time, e.g. "[Alice 0m52s] Bob 1m0s
+// Carol out", bracketing the player on the clock.
+func (c *playerClocks) String(now time.Time) string {
+	parts := []string{}
+	for _, player := range c.players {
+		part := player + " " + formatClock(c.Remaining(player, now))
+		if c.Out(player, now) {
+			part = player + " out"
+		}
+		if player == c.active {
+			part = "[" + part + "]"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// PlayTurns plays round in turns mode: for each prompt, every player with
+// time left answers in turn, and only the answering player's clock runs.
+// Players who use up TURN_BUDGET sit out the rest of the round. It returns
+// TIME_UP once every prompt has gone round or everyone is out of time,
+// ENDED_EARLY if the round was ended and QUIT if the players quit or input
+// ran out.
+func (g *Game) PlayTurns(round *Round, in *interrupter) timerResult {
+	fmt.Fprintln(g.out, SEP)
+	printLetters(g.out, round.Letters)
+	fmt.Fprintf(g.out, msg("turns-intro")+"\n", formatClock(TURN_BUDGET), SKIP_KEY, PAUSE_KEY, QUIT_KEY)
+	fmt.Fprintln(g.out, "")
+
+	ctx := in.startRound()
+	start := g.Clock.Now()
+	clocks := newPlayerClocks(PLAYERS, TURN_BUDGET, start)
+	result := TIME_UP
+turns:
+	for i, prompt := range round.Prompts {
+		for _, player := range clocks.Left(g.Clock.Now()) {
+			fmt.Fprintf(g.out, "%s, "+strings.TrimLeft(PROMPT_FORMAT, " ")+"\n", player, painted{COLOR_DIM, i + 1}, prompt)
+			g.mu.Lock()
+			g.live = liveRound{round, clocks.timers[player]}
+			g.mu.Unlock()
+			text, r := takeTurn(ctx, g.out, g.Clock, clocks, player, g.lines, g.Resolution)
+			if r != TIME_UP {
+				result = r
+				break turns
+			}
+			if text != "" {
+				round.Answers = append(round.Answers, newAnswer(round, player, i, text))
+			}
+		}
+		if len(clocks.Left(g.Clock.Now())) == 0 {
+			fmt.Fprintln(g.out, msg("all-out"))
+			break
+		}
+	}
+	in.endRound()
+	g.mu.Lock()
+	g.live.timer = nil
+	g.Stats.record(*round, result, g.Clock.Now().Sub(start))
+	g.mu.Unlock()
+	switch result {
+	case ENDED_EARLY:
+		fmt.Fprintln(g.out, msg("ended-early"))
+	case QUIT:
+		fmt.Fprintln(g.out, msg("bye"))
+	}
+	return result
+}
+
+// takeTurn runs player's clock on clock, redrawing every clock each
+// resolution, until they enter an answer, which it returns with TIME_UP. An
+// empty answer means they passed or ran out of time. PAUSE_KEY pauses or
+// resumes the whole game, stopping player's clock with it. It returns
+// ENDED_EARLY if ctx is cancelled or SKIP_KEY is entered, and QUIT on
+// QUIT_KEY or when lines runs out.
+func takeTurn(ctx context.Context, w io.Writer, clock Clock, clocks *playerClocks, player string, lines <-chan string, resolution time.Duration) (string, timerResult) {
+	clocks.Start(player, clock.Now())
+	defer func() { clocks.Stop(clock.Now()) }()
+	for now := clock.Now(); !clocks.Out(player, now); now = clock.Now() {
+		status := strings.Repeat(" ", visibleWidth(msg("paused-label")))
+		if clockPaused(clock) {
+			status = msg("paused-label")
+		}
+		fmt.Fprintf(w, "\r%s %s", clocks.String(now), status)
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(w)
+			return "", ENDED_EARLY
+		case line, ok := <-lines:
+			fmt.Fprintln(w)
+			if !ok {
+				return "", QUIT
+			}
+			switch text := strings.TrimSpace(line); text {
+			case SKIP_KEY:
+				return "", ENDED_EARLY
+			case QUIT_KEY:
+				return "", QUIT
+			case PAUSE_KEY:
+				togglePause(clock)
+			default:
+				return text, TIME_UP
+			}
+		case <-clock.After(min(resolution, clocks.Remaining(player, now))):
+		}
+	}
+	fmt.Fprintf(w, "\r%s \n"+msg("out-of-time")+"\n", clocks.String(clock.Now()), player)
+	return "", TIME_UP
+}
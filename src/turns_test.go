@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlayerClocks(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+	clocks := newPlayerClocks([]string{"Alice", "Bob", "Carol"}, time.Minute, now)
+
+	// Nobody's clock runs until their turn
+	if left := clocks.Remaining("Alice", at(time.Hour)); left != time.Minute {
+		t.Errorf("Alice has %s left before her first turn, want 1m", left)
+	}
+	clocks.Start("Alice", at(0))
+	clocks.Start("Bob", at(20*time.Second))
+	clocks.Stop(at(50 * time.Second))
+	clocks.Start("Alice", at(90*time.Second))
+	for _, want := range []struct {
+		player string
+		left   time.Duration
+	}{
+		{"Alice", 30 * time.Second},
+		{"Bob", 30 * time.Second},
+		{"Carol", time.Minute},
+	} {
+		if left := clocks.Remaining(want.player, at(100*time.Second)); left != want.left {
+			t.Errorf("%s has %s left, want %s", want.player, left, want.left)
+		}
+	}
+	if s := clocks.String(at(100 * time.Second)); s != "[Alice 0m30s]  Bob 0m30s  Carol 1m0s" {
+		t.Errorf("String = %q", s)
+	}
+
+	// Alice runs out, and is skipped from then on
+	end := at(130 * time.Second)
+	if !clocks.Out("Alice", end) || clocks.Remaining("Alice", end) != 0 {
+		t.Errorf("Alice isn't out with %s left", clocks.Remaining("Alice", end))
+	}
+	if left := clocks.Left(end); !slices.Equal(left, []string{"Bob", "Carol"}) {
+		t.Errorf("players left %v, want Bob and Carol", left)
+	}
+	clocks.Stop(end)
+	if s := clocks.String(end); s != "Alice out  Bob 0m30s  Carol 1m0s" {
+		t.Errorf("String = %q", s)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// voteOnAnswers asks the players to accept or reject each valid answer in
+// round, one line of votes per answer read from lines, and marks the ones
+// they reject. It reports false if input ran out.
+func voteOnAnswers(w io.Writer, round *Round, players []string, lines <-chan string, tieAccepts bool) bool {
+	fmt.Fprintf(w, msg("vote")+"\n", strings.Join(players, ", "))
+	for i := range round.Answers {
+		answer := &round.Answers[i]
+		if !answer.Valid {
+			continue
+		}
+		for {
+			fmt.Fprintf(w, "  %s, %d. %s: %s? ", answer.Player, answer.Prompt+1, round.Prompts[answer.Prompt], answer.Text)
+			line, ok := <-lines
+			if !ok {
+				fmt.Fprintln(w)
+				return false
+			}
+			accepted, err := tallyVotes(line, len(players), tieAccepts)
+			if err != nil {
+				fmt.Fprintf(w, "\t%v\n", err)
+				continue
+			}
+			answer.Rejected = !accepted
+			if answer.Rejected {
+				fmt.Fprintln(w, "\t"+msg("vote-rejected"))
+			}
+			break
+		}
+	}
+	return true
+}
+
+// tallyVotes counts a line of y/n votes, one per voter, and reports whether
+// the majority accepted. A blank line accepts; tieAccepts settles ties.
+func tallyVotes(votes string, voters int, tieAccepts bool) (bool, error) {
+	votes = strings.ToLower(strings.Join(strings.Fields(votes), ""))
+	if votes == "" {
+		return true, nil
+	}
+	if len(votes) != voters {
+		return false, fmt.Errorf(msg("vote-count"), voters, len(votes))
+	}
+	yes, no := 0, 0
+	for _, vote := range votes {
+		switch vote {
+		case 'y':
+			yes++
+		case 'n':
+			no++
+		default:
+			return false, fmt.Errorf(msg("vote-bad"), vote)
+		}
+	}
+	if yes == no {
+		return tieAccepts, nil
+	}
+	return yes > no, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTallyVotes(t *testing.T) {
+	for _, test := range []struct {
+		votes      string
+		voters     int
+		tieAccepts bool
+		want, ok   bool
+	}{
+		{"yyn", 3, false, true, true},
+		{"nny", 3, true, false, true},
+		{"Y N Y", 3, false, true, true},
+		{"", 3, false, true, true},
+		{"yn", 2, true, true, true},
+		{"yn", 2, false, false, true},
+		{"yy", 3, false, false, false},
+		{"yyyy", 3, false, false, false},
+		{"yx", 2, false, false, false},
+	} {
+		got, err := tallyVotes(test.votes, test.voters, test.tieAccepts)
+		if (err == nil) != test.ok || (test.ok && got != test.want) {
+			t.Errorf("tallyVotes(%q, %d, %v) = %v, %v; want %v, ok %v", test.votes, test.voters, test.tieAccepts, got, err, test.want, test.ok)
+		}
+	}
+}
+
+func TestVoteOnAnswers(t *testing.T) {
+	round := roundWith("B", map[string][]string{
+		"Al": {"Bear", "Apple"},
+		"Bo": {"Bison", "Banana"},
+	})
+	// Apple is invalid, so only three answers are put to the vote
+	lines := readLines(strings.NewReader("nn\nyes\nyy\n\n"))
+	var out bytes.Buffer
+	if !voteOnAnswers(&out, &round, []string{"Al", "Bo"}, lines, false) {
+		t.Fatalf("input ran out:\n%s", out.String())
+	}
+	rejected := []string{}
+	for _, answer := range round.Answers {
+		if answer.Rejected {
+			rejected = append(rejected, answer.Text)
+		}
+	}
+	if strings.Join(rejected, ",") != "Bear" {
+		t.Errorf("rejected %v, want just Bear", rejected)
+	}
+	if n := strings.Count(out.String(), "?"); n != 4 {
+		t.Errorf("asked %d times, want 4 with the bad vote asked again:\n%s", n, out.String())
+	}
+	points := scoreRound(&round, ScoringRules{Unique: 1})
+	if points["Al"] != 0 || points["Bo"] != 2 {
+		t.Errorf("scores %v, want Al on 0 with Bear rejected and Bo on 2", points)
+	}
+
+	if voteOnAnswers(&out, &round, []string{"Al", "Bo"}, readLines(strings.NewReader("")), false) {
+		t.Error("voting with no input reported finishing")
+	}
+}